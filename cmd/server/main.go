@@ -16,6 +16,12 @@ func main() {
 	}
 
 	app := app.New(cfg)
+	go func() {
+		if err := app.SyncInstallations(); err != nil {
+			log.Printf("Error syncing installations: %v", err)
+		}
+	}()
+
 	webhookHandler := handlers.NewWebhookHandler(app)
 
 	http.HandleFunc("/health", handlers.Health)
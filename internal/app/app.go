@@ -2,38 +2,167 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v74/github"
+	"github.com/taman9333/issue-estimate-reminder/internal/commands"
 	"github.com/taman9333/issue-estimate-reminder/internal/config"
+	"github.com/taman9333/issue-estimate-reminder/internal/estimate"
 	githubclient "github.com/taman9333/issue-estimate-reminder/internal/github"
-	"github.com/taman9333/issue-estimate-reminder/internal/utils"
+	"github.com/taman9333/issue-estimate-reminder/internal/installations"
+	"github.com/taman9333/issue-estimate-reminder/internal/scheduler"
 )
 
+// Version is the app's version, set at build time with
+// -ldflags "-X github.com/taman9333/issue-estimate-reminder/internal/app.Version=v1.2.0"
+var Version = "dev"
+
+// clientFactory creates authenticated GitHub clients, tests point them at
+// a fake GitHub
+type clientFactory interface {
+	CreateAppClient() (*github.Client, error)
+	CreateInstallationClient(installationID int64) (*github.Client, error)
+	ForgetInstallation(installationID int64)
+}
+
 type App struct {
-	config       *config.Config
-	githubClient *githubclient.Client
+	config        *config.Config
+	githubClient  clientFactory
+	scheduler     *scheduler.Scheduler
+	installations *installations.Registry
+	onboarding    onboarding
+	identity      identity
 }
 
-var reminderMessage = `Hello! Please add a time estimate to this issue.
+// identity caches the login of the app's bot user, "<slug>[bot]"
+type identity struct {
+	mu    sync.Mutex
+	login string
+}
 
-Format: Estimate: X days
+func New(cfg *config.Config) *App {
+	return &App{
+		config:        cfg,
+		githubClient:  githubclient.New(cfg),
+		scheduler:     scheduler.New(),
+		installations: installations.NewRegistry(),
+	}
+}
 
-Example: Estimate: 3 days
+// parserFor builds an estimate parser using the repository's settings
+func (a *App) parserFor(repo *github.Repository) *estimate.Parser {
+	repoConfig := a.config.ForRepo(repoFullName(repo))
+	// bounds are validated when the config is loaded
+	minDuration, maxDuration, _ := a.config.EstimateBounds(repoConfig)
+	return estimate.NewParser(estimate.Options{
+		Scheme:           repoConfig.Scheme,
+		WorkdayLength:    a.config.WorkdayLength,
+		SprintDays:       a.config.SprintDays,
+		Points:           repoConfig.StoryPoints,
+		Sizes:            repoConfig.TShirtSizes,
+		LabelPatterns:    repoConfig.LabelPatterns,
+		FormHeading:      repoConfig.FormHeading,
+		TitlePatterns:    repoConfig.TitlePatterns,
+		FrontMatterField: repoConfig.FrontMatterField,
+		Locales:          repoConfig.Locales,
+		MinDuration:      minDuration,
+		MaxDuration:      maxDuration,
+		DeferralTokens:   repoConfig.DeferralTokens,
+	})
+}
 
-Thanks!`
+// issueFor converts a GitHub issue to what estimate detectors look at
+func issueFor(issue *github.Issue) estimate.Issue {
+	return estimate.Issue{
+		Title:  issue.GetTitle(),
+		Body:   issue.GetBody(),
+		Labels: labelNames(issue),
+	}
+}
 
-func New(cfg *config.Config) *App {
-	return &App{
-		config:       cfg,
-		githubClient: githubclient.New(cfg),
+// splitByConfidence separates the natural language estimates that need
+// confirmation, the most confident of those first, from the ones that are
+// accepted. Other estimates are explicit whatever their Confidence, custom
+// detectors may leave it unset.
+func splitByConfidence(ests []*estimate.Estimate, minConfidence float64) ([]*estimate.Estimate, []*estimate.Estimate) {
+	var accepted, tentative []*estimate.Estimate
+	for _, est := range ests {
+		if !est.Natural || est.Confidence >= minConfidence {
+			accepted = append(accepted, est)
+		} else {
+			tentative = append(tentative, est)
+		}
+	}
+	sort.SliceStable(tentative, func(i, j int) bool { return tentative[i].Confidence > tentative[j].Confidence })
+	return accepted, tentative
+}
+
+func labelNames(issue *github.Issue) []string {
+	names := make([]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		names = append(names, label.GetName())
+	}
+	return names
+}
+
+func repoFullName(repo *github.Repository) string {
+	return repo.GetOwner().GetLogin() + "/" + repo.GetName()
+}
+
+// assessment is what the app found out about an issue's estimate
+type assessment struct {
+	parser *estimate.Parser
+	// res is the estimate that counts, only set when err is nil
+	res estimate.Resolution
+	// tentative are natural language estimates that need confirmation
+	tentative []*estimate.Estimate
+	tasks     *estimate.TaskList
+	taskErr   error
+	// err is an *estimate.InvalidError for a rejected or deferred estimate,
+	// or estimate.ErrNoEstimate
+	err error
+}
+
+// assess runs the repository's estimate detectors on an issue and the
+// comments allowed to carry its estimate
+func (a *App) assess(repo *github.Repository, issue *github.Issue, comments []string) *assessment {
+	repoConfig := a.config.ForRepo(repoFullName(repo))
+	as := &assessment{parser: a.parserFor(repo)}
+
+	detected := issueFor(issue)
+	detected.Comments = comments
+	ests, err := as.parser.Detect(detected, repoConfig.Detectors)
+	ests, as.tentative = splitByConfidence(ests, repoConfig.MinConfidence)
+	if err == nil && len(ests) == 0 {
+		err = estimate.ErrNoEstimate
+	}
+
+	// the task list total only counts when the issue has no estimate of its own
+	as.tasks, as.taskErr = as.parser.ParseTasks(issue.GetBody())
+	switch {
+	case err != nil && as.tasks.Total != nil && as.taskErr == nil:
+		ests, err = []*estimate.Estimate{as.tasks.Total}, nil
+	case errors.Is(err, estimate.ErrNoEstimate) && as.taskErr != nil:
+		err = as.taskErr
 	}
+
+	as.err = err
+	if err == nil {
+		as.res = estimate.Resolve(ests, repoConfig.ConflictRule)
+	}
+	return as
 }
 
 func (a *App) HandleIssueOpened(payload *github.IssuesEvent) error {
 	issue := payload.GetIssue()
-	repo := payload.GetRepo()
 	installation := payload.GetInstallation()
 
 	if installation == nil {
@@ -41,26 +170,511 @@ func (a *App) HandleIssueOpened(payload *github.IssuesEvent) error {
 	}
 
 	log.Printf("Processing issue #%d: %s", issue.GetNumber(), issue.GetTitle())
+	return a.checkIssue(installation.GetID(), payload.GetRepo(), issue, true)
+}
+
+// HandleIssueEdited checks an edited issue again, resolving the reminder
+// once it has an estimate and reminding again if the estimate was removed
+func (a *App) HandleIssueEdited(payload *github.IssuesEvent) error {
+	issue := payload.GetIssue()
+	installation := payload.GetInstallation()
+
+	if installation == nil {
+		return fmt.Errorf("no installation found in payload")
+	}
+	if issue.GetState() == "closed" {
+		return nil
+	}
+
+	log.Printf("Rechecking edited issue #%d: %s", issue.GetNumber(), issue.GetTitle())
+	return a.checkIssue(installation.GetID(), payload.GetRepo(), issue, false)
+}
+
+// HandleIssueLabeled checks an issue again when a label that exempts it
+// or carries its estimate is added, retracting the reminder if the issue
+// no longer needs it
+func (a *App) HandleIssueLabeled(payload *github.IssuesEvent) error {
+	return a.recheckLabeled(payload)
+}
+
+// HandleIssueUnlabeled checks an issue again when a label that exempted
+// it or carried its estimate is removed, restoring the reminder if the
+// issue needs an estimate again
+func (a *App) HandleIssueUnlabeled(payload *github.IssuesEvent) error {
+	return a.recheckLabeled(payload)
+}
+
+func (a *App) recheckLabeled(payload *github.IssuesEvent) error {
+	issue := payload.GetIssue()
+	repo := payload.GetRepo()
+	installation := payload.GetInstallation()
+
+	if installation == nil {
+		return fmt.Errorf("no installation found in payload")
+	}
+	if issue.GetState() == "closed" {
+		return nil
+	}
+
+	// the app's own deferral label and unrelated labels change nothing
+	label := payload.GetLabel().GetName()
+	if !a.isExemptLabel(repo, label) && !a.parserFor(repo).IsEstimateLabel(label) {
+		return nil
+	}
+
+	// commands that change labels check the issue themselves
+	login, err := a.login()
+	if err != nil {
+		return err
+	}
+	if isUser(payload.GetSender(), login) {
+		return nil
+	}
+
+	log.Printf("Rechecking issue #%d after label %q was %s", issue.GetNumber(), label, payload.GetAction())
+	return a.checkIssue(installation.GetID(), repo, issue, false)
+}
+
+// HandleIssueReopened handles a reopened issue as the repository's
+// ReopenedIssues setting says, it is new work so an estimate it had may
+// no longer hold
+func (a *App) HandleIssueReopened(payload *github.IssuesEvent) error {
+	issue := payload.GetIssue()
+	repo := payload.GetRepo()
+	installation := payload.GetInstallation()
+
+	if installation == nil {
+		return fmt.Errorf("no installation found in payload")
+	}
+
+	switch a.config.ForRepo(repoFullName(repo)).ReopenedIssues {
+	case config.ReopenedIgnore:
+		log.Printf("Ignoring reopened issue #%d", issue.GetNumber())
+		return nil
+	case config.ReopenedReestimate:
+		comments, err := a.estimateComments(installation.GetID(), repo, issue)
+		if err != nil {
+			return err
+		}
+		as := a.assess(repo, issue, comments)
+		if as.err == nil {
+			log.Printf("Asking for a re-estimate of reopened issue #%d", issue.GetNumber())
+			_, err := a.remind(installation.GetID(), repo, issue.GetNumber(),
+				reestimateMessage(as.parser, as.res.Estimate), false)
+			return err
+		}
+	}
+
+	log.Printf("Rechecking reopened issue #%d: %s", issue.GetNumber(), issue.GetTitle())
+	return a.checkIssue(installation.GetID(), repo, issue, false)
+}
+
+// HandleIssueComment checks an issue again when a comment gives it an
+// estimate, only comments by users with the repository's
+// CommentEstimateRole count. Deleting such a comment checks it again too.
+func (a *App) HandleIssueComment(payload *github.IssueCommentEvent) error {
+	issue := payload.GetIssue()
+	repo := payload.GetRepo()
+	comment := payload.GetComment()
+	installation := payload.GetInstallation()
+
+	if installation == nil {
+		return fmt.Errorf("no installation found in payload")
+	}
+	if issue.IsPullRequest() || issue.GetState() == "closed" || comment.GetUser().GetType() == "Bot" {
+		return nil
+	}
+
+	// commands run once, editing the comment doesn't repeat them
+	if payload.GetAction() == "created" {
+		if cmds := knownCommands(commands.Parse(comment.GetBody())); len(cmds) > 0 {
+			return a.runCommands(installation.GetID(), repo, issue, comment, cmds)
+		}
+	}
+
+	// an edit that removes an estimate matters as much as one that adds it
+	parser := a.parserFor(repo)
+	hasEstimate := func(body string) bool {
+		_, err := parser.Parse(body)
+		return err == nil
+	}
+	estimated := hasEstimate(comment.GetBody())
+	wasEstimated := payload.GetAction() == "edited" && hasEstimate(payload.GetChanges().GetBody().GetFrom())
+	if !estimated && !wasEstimated {
+		return nil
+	}
+
+	if estimated && !wasEstimated && payload.GetAction() != "deleted" {
+		allowed, err := a.canEstimateInComments(installation.GetID(), repo, issue, comment)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			log.Printf("Ignoring estimate on issue #%d from %s, who may not estimate it",
+				issue.GetNumber(), comment.GetUser().GetLogin())
+			return nil
+		}
+	}
+
+	log.Printf("Rechecking issue #%d after a comment with an estimate", issue.GetNumber())
+	return a.checkIssue(installation.GetID(), repo, issue, false)
+}
+
+// canEstimateInComments reports whether the author of comment has the
+// repository's CommentEstimateRole
+func (a *App) canEstimateInComments(installationID int64, repo *github.Repository, issue *github.Issue, comment *github.IssueComment) (bool, error) {
+	role := a.config.ForRepo(repoFullName(repo)).CommentEstimateRole
+	if role == config.RoleNone {
+		return false, nil
+	}
+
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return false, fmt.Errorf("failed to create installation client: %v", err)
+	}
+	return newPermissions(client, repo, issue).has(comment.GetUser(), comment.GetAuthorAssociation(), role)
+}
+
+// estimateComments returns the comments on an issue that hold an estimate
+// and were written by users with the repository's CommentEstimateRole,
+// oldest first
+func (a *App) estimateComments(installationID int64, repo *github.Repository, issue *github.Issue) ([]string, error) {
+	role := a.config.ForRepo(repoFullName(repo)).CommentEstimateRole
+	if role == config.RoleNone || issue.GetComments() == 0 {
+		return nil, nil
+	}
+
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	parser := a.parserFor(repo)
+	perms := newPermissions(client, repo, issue)
+	var bodies []string
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := client.Issues.ListComments(context.Background(),
+			repo.GetOwner().GetLogin(), repo.GetName(), issue.GetNumber(), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments: %v", err)
+		}
+
+		for _, comment := range comments {
+			if comment.GetUser().GetType() == "Bot" {
+				continue
+			}
+			if _, err := parser.Parse(comment.GetBody()); err != nil {
+				continue
+			}
+			allowed, err := perms.has(comment.GetUser(), comment.GetAuthorAssociation(), role)
+			if err != nil {
+				return nil, err
+			}
+			if allowed {
+				bodies = append(bodies, comment.GetBody())
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return bodies, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// checkIssue looks for the issue's estimate and comments on what is wrong
+// with it. A new issue has no comments from the app yet, an existing one
+// gets its reminder updated, or resolved once the estimate is fine. The
+// estimate checks of pull requests closing the issue are published again
+// when its reminder changes.
+func (a *App) checkIssue(installationID int64, repo *github.Repository, issue *github.Issue, isNew bool) error {
+	changed, err := a.updateReminder(installationID, repo, issue, isNew)
+	if err != nil || !changed || isNew {
+		return err
+	}
+	return a.refreshEstimateChecks(installationID, repo, issue.GetNumber())
+}
+
+// updateReminder does the work of checkIssue, reporting whether the
+// reminder changed
+func (a *App) updateReminder(installationID int64, repo *github.Repository, issue *github.Issue, isNew bool) (bool, error) {
+	if a.isExempt(repo, issue) {
+		log.Printf("Issue #%d doesn't need an estimate", issue.GetNumber())
+		if isNew {
+			return false, nil
+		}
+		return a.clearReminder(installationID, repo, issue, exemptMessage)
+	}
+
+	repoConfig := a.config.ForRepo(repoFullName(repo))
+	var comments []string
+	if !isNew {
+		var err error
+		if comments, err = a.estimateComments(installationID, repo, issue); err != nil {
+			return false, err
+		}
+	}
+	as := a.assess(repo, issue, comments)
+	number := issue.GetNumber()
+
+	if repoConfig.TaskBreakdown && as.tasks.Estimated() > 0 {
+		if _, err := a.upsertComment(installationID, repo, number,
+			taskBreakdownMarker, taskBreakdownMessage(as.tasks, as.taskErr)); err != nil {
+			return false, err
+		}
+	}
+
+	if as.err == nil {
+		log.Printf("Issue #%d has an estimate of %s (%s, line %d)",
+			number, as.res.Estimate, as.res.Estimate.Source, as.res.Estimate.Line)
+		if as.res.Ambiguous() {
+			log.Printf("Issue #%d has %d conflicting estimates", number, len(as.res.All))
+			return a.remind(installationID, repo, number, clarificationMessage(as.res), isNew)
+		}
+		if isNew {
+			return false, nil
+		}
+		return a.clearReminder(installationID, repo, issue, resolvedMessage(as.res.Estimate))
+	}
+
+	if len(as.tentative) > 0 {
+		log.Printf("Issue #%d may have an estimate of %s (confidence %.2f)",
+			number, as.tentative[0], as.tentative[0].Confidence)
+		return a.remind(installationID, repo, number, confirmationMessage(as.parser, as.tentative[0]), isNew)
+	}
+
+	if errors.Is(as.err, estimate.ErrDeferred) {
+		log.Printf("Issue #%d has a deferred estimate: %v", number, as.err)
+		// the issue still has no estimate, so its pull requests' checks
+		// stay as they are
+		if !isNew {
+			if _, err := a.resolveComment(installationID, repo, number, reminderMarker, deferredMessage); err != nil {
+				return false, err
+			}
+		}
+		return false, a.deferEstimate(installationID, repo, issue)
+	}
+
+	message := reminderMessage(as.parser)
+	var invalidErr *estimate.InvalidError
+	if errors.As(as.err, &invalidErr) {
+		log.Printf("Issue #%d has a rejected estimate: %v", number, invalidErr)
+		message = rejectionMessage(as.parser, invalidErr)
+	}
+
+	return a.remind(installationID, repo, number, message, isNew)
+}
 
-	if utils.HasEstimate(issue.GetBody()) {
-		log.Printf("Issue #%d has an estimate", issue.GetNumber())
+// isExempt reports whether an issue is labeled as not needing an estimate
+func (a *App) isExempt(repo *github.Repository, issue *github.Issue) bool {
+	for _, name := range labelNames(issue) {
+		if a.isExemptLabel(repo, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExemptLabel reports whether a label marks issues as not needing an
+// estimate, label names are case insensitive on GitHub
+func (a *App) isExemptLabel(repo *github.Repository, name string) bool {
+	repoConfig := a.config.ForRepo(repoFullName(repo))
+	if repoConfig.NoEstimateLabel != "" && strings.EqualFold(name, repoConfig.NoEstimateLabel) {
+		return true
+	}
+	return slices.ContainsFunc(repoConfig.ExemptLabels, func(label string) bool {
+		return strings.EqualFold(name, label)
+	})
+}
+
+// remind posts the app's reminder on an issue, or updates the one it
+// posted before. It reports whether the reminder changed.
+func (a *App) remind(installationID int64, repo *github.Repository, number int, message string, isNew bool) (bool, error) {
+	if isNew {
+		return true, a.postComment(installationID, repo, number, reminderMarker+"\n"+message)
+	}
+	return a.upsertComment(installationID, repo, number, reminderMarker, message)
+}
+
+// clearReminder resolves the reminder and any deferral of an issue that
+// now has an estimate or doesn't need one, resolution says which. It
+// reports whether the reminder changed.
+func (a *App) clearReminder(installationID int64, repo *github.Repository, issue *github.Issue, resolution string) (bool, error) {
+	number := issue.GetNumber()
+	if a.scheduler.Cancel(issueKey(repo, number)) {
+		log.Printf("Cancelled the follow up on issue #%d", number)
+	}
+
+	label := a.config.ForRepo(repoFullName(repo)).DeferralLabel
+	if label != "" && slices.Contains(labelNames(issue), label) {
+		if err := a.removeLabel(installationID, repo, number, label); err != nil {
+			return false, err
+		}
+	}
+
+	return a.resolveComment(installationID, repo, number, reminderMarker, resolution)
+}
+
+// deferEstimate labels an issue whose estimate was put off and schedules
+// a check for whether it has one after the repository's follow up days
+func (a *App) deferEstimate(installationID int64, repo *github.Repository, issue *github.Issue) error {
+	repoConfig := a.config.ForRepo(repoFullName(repo))
+	number := issue.GetNumber()
+	label := repoConfig.DeferralLabel
+	if label != "" && !slices.Contains(labelNames(issue), label) {
+		if err := a.addLabel(installationID, repo, number, label); err != nil {
+			return err
+		}
+	}
+
+	if a.scheduler.Pending(issueKey(repo, number)) {
 		return nil
 	}
+	if days := repoConfig.DeferralFollowUpDays; days > 0 {
+		a.scheduler.Schedule(issueKey(repo, number), time.Duration(days)*24*time.Hour, a.followUpJob(installationID, repo, number))
+		log.Printf("Scheduled a follow up on issue #%d in %d days", number, days)
+	}
+	return nil
+}
 
-	client, err := a.githubClient.CreateInstallationClient(installation.GetID())
+func (a *App) followUpJob(installationID int64, repo *github.Repository, number int) func() {
+	return func() {
+		if a.suspended(installationID) {
+			return
+		}
+		if err := a.followUpDeferral(installationID, repo, number); err != nil {
+			log.Printf("Error following up on issue #%d: %v", number, err)
+		}
+	}
+}
+
+// followUpDeferral reminds about a deferred estimate that is still missing,
+// or drops the deferral label once the issue has an estimate
+func (a *App) followUpDeferral(installationID int64, repo *github.Repository, number int) error {
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	issue, _, err := client.Issues.Get(context.Background(), repo.GetOwner().GetLogin(), repo.GetName(), number)
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %v", err)
+	}
+	if issue.GetState() == "closed" || a.isExempt(repo, issue) {
+		return nil
+	}
+
+	comments, err := a.estimateComments(installationID, repo, issue)
+	if err != nil {
+		return err
+	}
+
+	repoConfig := a.config.ForRepo(repoFullName(repo))
+	as := a.assess(repo, issue, comments)
+	if as.err == nil {
+		if repoConfig.DeferralLabel == "" {
+			return nil
+		}
+		return a.removeLabel(installationID, repo, number, repoConfig.DeferralLabel)
+	}
+
+	_, err = a.upsertComment(installationID, repo, number, reminderMarker, followUpMessage(as.parser, repoConfig.DeferralFollowUpDays))
+	return err
+}
+
+// restoreFollowUps schedules the follow up of every open issue of repo
+// with the deferral label, due the repository's follow up days after the
+// label was added
+func (a *App) restoreFollowUps(installationID int64, repo *github.Repository) error {
+	repoConfig := a.config.ForRepo(repoFullName(repo))
+	days, label := repoConfig.DeferralFollowUpDays, repoConfig.DeferralLabel
+	if days <= 0 || label == "" {
+		return nil
+	}
+
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	ctx := context.Background()
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	opts := &github.IssueListByRepoOptions{
+		State:       "open",
+		Labels:      []string{label},
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		issues, resp, err := client.Issues.ListByRepo(ctx, owner, name, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list issues: %v", err)
+		}
+		for _, issue := range issues {
+			number := issue.GetNumber()
+			if issue.IsPullRequest() || a.scheduler.Pending(issueKey(repo, number)) {
+				continue
+			}
+
+			labeledAt, err := labeledAt(client, repo, number, label)
+			if err != nil {
+				return err
+			}
+			delay := max(time.Until(labeledAt.Add(time.Duration(days)*24*time.Hour)), 0)
+			a.scheduler.Schedule(issueKey(repo, number), delay, a.followUpJob(installationID, repo, number))
+			log.Printf("Restored the follow up on issue #%d in %v", number, delay.Round(time.Minute))
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.ListOptions.Page = resp.NextPage
+	}
+}
+
+// labeledAt returns when label was last added to an issue, or now if its
+// events don't say
+func labeledAt(client *github.Client, repo *github.Repository, number int, label string) (time.Time, error) {
+	at := time.Now()
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		events, resp, err := client.Issues.ListIssueEvents(context.Background(),
+			repo.GetOwner().GetLogin(), repo.GetName(), number, opts)
+		if err != nil {
+			return at, fmt.Errorf("failed to list issue events: %v", err)
+		}
+		for _, event := range events {
+			if event.GetEvent() == "labeled" && event.GetLabel().GetName() == label {
+				at = event.GetCreatedAt().Time
+			}
+		}
+		if resp.NextPage == 0 {
+			return at, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// issueKey identifies an issue across repositories, e.g. "acme/api#12"
+func issueKey(repo *github.Repository, number int) string {
+	return fmt.Sprintf("%s#%d", repoFullName(repo), number)
+}
+
+// postComment comments on an issue as the app installation
+func (a *App) postComment(installationID int64, repo *github.Repository, number int, body string) error {
+	client, err := a.githubClient.CreateInstallationClient(installationID)
 	if err != nil {
 		return fmt.Errorf("failed to create installation client: %v", err)
 	}
 
 	comment := &github.IssueComment{
-		Body: &reminderMessage,
+		Body: &body,
 	}
 
 	_, _, err = client.Issues.CreateComment(
 		context.Background(),
 		repo.GetOwner().GetLogin(),
 		repo.GetName(),
-		issue.GetNumber(),
+		number,
 		comment,
 	)
 
@@ -68,10 +682,207 @@ func (a *App) HandleIssueOpened(payload *github.IssuesEvent) error {
 		return fmt.Errorf("failed to create comment: %v", err)
 	}
 
-	log.Printf("Posted reminder comment on issue #%d", issue.GetNumber())
+	log.Printf("Posted comment on issue #%d", number)
+	return nil
+}
+
+// findComment returns the app's comment on an issue containing marker, or
+// nil if there is none. Comments by anyone else are skipped even when they
+// quote the marker.
+func (a *App) findComment(client *github.Client, repo *github.Repository, number int, marker string) (*github.IssueComment, error) {
+	login, err := a.login()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := client.Issues.ListComments(context.Background(),
+			repo.GetOwner().GetLogin(), repo.GetName(), number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments: %v", err)
+		}
+
+		for _, comment := range comments {
+			if isUser(comment.GetUser(), login) && strings.Contains(comment.GetBody(), marker) {
+				return comment, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// login returns the login of the app's bot user, looking it up once
+func (a *App) login() (string, error) {
+	a.identity.mu.Lock()
+	defer a.identity.mu.Unlock()
+
+	if a.identity.login != "" {
+		return a.identity.login, nil
+	}
+
+	client, err := a.githubClient.CreateAppClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to create app client: %v", err)
+	}
+	app, _, err := client.Apps.Get(context.Background(), "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get app: %v", err)
+	}
+
+	a.identity.login = app.GetSlug() + "[bot]"
+	return a.identity.login, nil
+}
+
+// isUser reports whether user is the bot user with login
+func isUser(user *github.User, login string) bool {
+	return user.GetType() == "Bot" && strings.EqualFold(user.GetLogin(), login)
+}
+
+// upsertComment updates the app's comment on an issue that contains marker,
+// or posts body as a new comment if there is none yet. It reports whether
+// the comment changed.
+func (a *App) upsertComment(installationID int64, repo *github.Repository, number int, marker, body string) (bool, error) {
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return false, fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	ctx := context.Background()
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	body = marker + "\n" + body
+
+	existing, err := a.findComment(client, repo, number, marker)
+	if err != nil {
+		return false, err
+	}
+
+	if existing != nil {
+		if existing.GetBody() == body {
+			return false, nil
+		}
+		if _, _, err := client.Issues.EditComment(ctx, owner, name, existing.GetID(),
+			&github.IssueComment{Body: &body}); err != nil {
+			return false, fmt.Errorf("failed to update comment: %v", err)
+		}
+		log.Printf("Updated comment on issue #%d", number)
+		return true, nil
+	}
+
+	if _, _, err := client.Issues.CreateComment(ctx, owner, name, number,
+		&github.IssueComment{Body: &body}); err != nil {
+		return false, fmt.Errorf("failed to create comment: %v", err)
+	}
+
+	log.Printf("Posted comment on issue #%d", number)
+	return true, nil
+}
+
+// resolveComment deletes the app's comment containing marker, or replaces
+// it with resolution when the repository keeps resolved reminders. It
+// reports whether the comment changed.
+func (a *App) resolveComment(installationID int64, repo *github.Repository, number int, marker, resolution string) (bool, error) {
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return false, fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	existing, err := a.findComment(client, repo, number, marker)
+	if err != nil || existing == nil {
+		return false, err
+	}
+
+	ctx := context.Background()
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+
+	if a.config.ForRepo(repoFullName(repo)).ResolvedReminders == config.ResolvedUpdate {
+		body := marker + "\n" + resolution
+		if existing.GetBody() == body {
+			return false, nil
+		}
+		if _, _, err := client.Issues.EditComment(ctx, owner, name, existing.GetID(),
+			&github.IssueComment{Body: &body}); err != nil {
+			return false, fmt.Errorf("failed to update comment: %v", err)
+		}
+		log.Printf("Marked reminder on issue #%d as resolved", number)
+		return true, nil
+	}
+
+	if _, err := client.Issues.DeleteComment(ctx, owner, name, existing.GetID()); err != nil {
+		return false, fmt.Errorf("failed to delete comment: %v", err)
+	}
+	log.Printf("Deleted reminder on issue #%d", number)
+	return true, nil
+}
+
+// addLabel adds a label to an issue, creating the label if needed
+func (a *App) addLabel(installationID int64, repo *github.Repository, number int, label string) error {
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	_, _, err = client.Issues.AddLabelsToIssue(
+		context.Background(),
+		repo.GetOwner().GetLogin(),
+		repo.GetName(),
+		number,
+		[]string{label},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add label: %v", err)
+	}
+
+	log.Printf("Added label %q to issue #%d", label, number)
+	return nil
+}
+
+// removeLabel removes a label from an issue, a label the issue doesn't
+// have is not an error
+func (a *App) removeLabel(installationID int64, repo *github.Repository, number int, label string) error {
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	resp, err := client.Issues.RemoveLabelForIssue(
+		context.Background(),
+		repo.GetOwner().GetLogin(),
+		repo.GetName(),
+		number,
+		label,
+	)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return fmt.Errorf("failed to remove label: %v", err)
+	}
+
+	log.Printf("Removed label %q from issue #%d", label, number)
 	return nil
 }
 
 func (a *App) GetWebhookSecret() string {
 	return a.config.WebhookSecret
 }
+
+// RequiredEvents returns the webhook events the app needs with its
+// configuration, installation events are always sent to apps
+func (a *App) RequiredEvents() []string {
+	events := []string{"issue_comment", "issues"}
+	if a.checksPullRequests() {
+		events = append(events, "check_run", "pull_request")
+	}
+	return events
+}
+
+// checksPullRequests reports whether any repository has PullRequestCheck
+func (a *App) checksPullRequests() bool {
+	check := a.config.Defaults.PullRequestCheck
+	for _, repoConfig := range a.config.Repos {
+		check = check || repoConfig.PullRequestCheck
+	}
+	return check
+}
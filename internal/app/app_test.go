@@ -0,0 +1,305 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taman9333/issue-estimate-reminder/internal/config"
+	"github.com/taman9333/issue-estimate-reminder/internal/estimate"
+	"github.com/taman9333/issue-estimate-reminder/internal/installations"
+	"github.com/taman9333/issue-estimate-reminder/internal/scheduler"
+)
+
+var testRepo = &github.Repository{
+	Owner:    &github.User{Login: github.Ptr("acme")},
+	Name:     github.Ptr("api"),
+	FullName: github.Ptr("acme/api"),
+}
+
+// newTestApp creates an app talking to a fake GitHub, configured from the
+// environment like the server with env on top
+func newTestApp(t *testing.T, env map[string]string) (*App, *fakeGitHub) {
+	t.Setenv("GITHUB_APP_ID", "1")
+	t.Setenv("WEBHOOK_SECRET", "test_secret")
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	gh := newFakeGitHub(t)
+	return &App{
+		config:        cfg,
+		githubClient:  gh,
+		scheduler:     scheduler.New(),
+		installations: installations.NewRegistry(),
+	}, gh
+}
+
+// issue builds the issue number 1 as GitHub would send it, with the
+// comments and labels the fake has for it
+func (f *fakeGitHub) issue(body, state string) *github.Issue {
+	return f.numberedIssue(1, body, state)
+}
+
+func (f *fakeGitHub) numberedIssue(number int, body, state string) *github.Issue {
+	return &github.Issue{
+		Number:   github.Ptr(number),
+		Title:    github.Ptr("Fix login redirect"),
+		Body:     github.Ptr(body),
+		State:    github.Ptr(state),
+		User:     &github.User{Login: github.Ptr("alice")},
+		Labels:   labelsOf(f.issueLabels(number)),
+		Comments: github.Ptr(len(f.bodies(number))),
+	}
+}
+
+func issuesEvent(action string, issue *github.Issue) *github.IssuesEvent {
+	return &github.IssuesEvent{
+		Action:       github.Ptr(action),
+		Issue:        issue,
+		Repo:         testRepo,
+		Installation: &github.Installation{ID: github.Ptr(int64(67890))},
+	}
+}
+
+func commentEvent(action string, issue *github.Issue, comment *github.IssueComment) *github.IssueCommentEvent {
+	return &github.IssueCommentEvent{
+		Action:       github.Ptr(action),
+		Issue:        issue,
+		Comment:      comment,
+		Repo:         testRepo,
+		Installation: &github.Installation{ID: github.Ptr(int64(67890))},
+	}
+}
+
+// reminders returns the app's reminder comments on issue 1
+func reminders(gh *fakeGitHub) []string {
+	var found []string
+	for _, body := range gh.bodies(1) {
+		if strings.Contains(body, reminderMarker) {
+			found = append(found, body)
+		}
+	}
+	return found
+}
+
+func TestHandleIssueEdited(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		resolved []string // reminders left once the estimate is added
+	}{
+		{name: "delete resolved reminders", resolved: nil},
+		{
+			name:     "update resolved reminders",
+			env:      map[string]string{"RESOLVED_REMINDERS": config.ResolvedUpdate},
+			resolved: []string{reminderMarker + "\nThanks! This issue now has an estimate of 3 days."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, gh := newTestApp(t, tt.env)
+
+			require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Login fails", "open"))))
+			require.Len(t, reminders(gh), 1)
+
+			require.NoError(t, app.HandleIssueEdited(issuesEvent("edited", gh.issue("Login fails\n\nEstimate: 3 days", "open"))))
+			assert.Equal(t, tt.resolved, reminders(gh), "reminder once the estimate is added")
+
+			require.NoError(t, app.HandleIssueEdited(issuesEvent("edited", gh.issue("Login fails", "open"))))
+			restored := reminders(gh)
+			require.Len(t, restored, 1, "reminder once the estimate is removed again")
+			assert.Contains(t, restored[0], "Estimate: X days")
+		})
+	}
+}
+
+func TestHandleIssueEdited_ClosedIssue(t *testing.T) {
+	app, gh := newTestApp(t, nil)
+
+	require.NoError(t, app.HandleIssueEdited(issuesEvent("edited", gh.issue("Login fails", "closed"))))
+	assert.Empty(t, gh.bodies(1))
+}
+
+func TestHandleIssueEdited_QuotedReminder(t *testing.T) {
+	app, gh := newTestApp(t, nil)
+
+	// someone quoting the app's reminder copies its marker too
+	quote := gh.addComment(1, "bob", "User", "MEMBER", "> "+reminderMarker+"\n> Please add an estimate\n\nOn it")
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Login fails", "open"))))
+	require.NoError(t, app.HandleIssueEdited(issuesEvent("edited", gh.issue("Login fails\n\nEstimate: 3 days", "open"))))
+
+	assert.Equal(t, []string{quote.GetBody()}, gh.bodies(1), "only the app's own reminder is resolved")
+}
+
+func TestDeferEstimate(t *testing.T) {
+	app, gh := newTestApp(t, nil)
+
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Login fails", "open"))))
+	require.Len(t, reminders(gh), 1)
+
+	require.NoError(t, app.HandleIssueEdited(issuesEvent("edited", gh.issue("Estimate: TBD", "open"))))
+	assert.Empty(t, reminders(gh), "putting the estimate off resolves the reminder")
+	assert.Equal(t, []string{"needs-estimate"}, gh.issueLabels(1))
+	assert.True(t, app.scheduler.Pending("acme/api#1"))
+
+	gh.issues[1] = gh.issue("Estimate: TBD", "open")
+	require.NoError(t, app.followUpDeferral(67890, testRepo, 1))
+	followUp := reminders(gh)
+	require.Len(t, followUp, 1, "the follow up reminds again while the estimate is missing")
+	assert.Contains(t, followUp[0], "7 days")
+
+	gh.issues[1] = gh.issue("Estimate: 3 days", "open")
+	require.NoError(t, app.followUpDeferral(67890, testRepo, 1))
+	assert.Empty(t, gh.issueLabels(1), "the follow up drops the label once the issue is estimated")
+}
+
+func TestRestoreFollowUps(t *testing.T) {
+	app, gh := newTestApp(t, nil)
+	labeled := func(label string, daysAgo int) *github.IssueEvent {
+		return &github.IssueEvent{
+			Event:     github.Ptr("labeled"),
+			Label:     &github.Label{Name: github.Ptr(label)},
+			CreatedAt: &github.Timestamp{Time: time.Now().Add(-time.Duration(daysAgo) * 24 * time.Hour)},
+		}
+	}
+
+	gh.labels[1] = []string{"needs-estimate"}
+	gh.issues[1] = gh.numberedIssue(1, "Estimate: TBD", "open")
+	gh.events[1] = []*github.IssueEvent{labeled("bug", 5), labeled("needs-estimate", 3)}
+	gh.issues[2] = gh.numberedIssue(2, "Estimate: 2 days", "open")
+	gh.labels[3] = []string{"needs-estimate"}
+	gh.issues[3] = gh.numberedIssue(3, "Estimate: TBD", "closed")
+
+	require.NoError(t, app.restoreFollowUps(67890, testRepo))
+	assert.True(t, app.scheduler.Pending("acme/api#1"), "the deferred issue gets its follow up back")
+	assert.False(t, app.scheduler.Pending("acme/api#2"), "the issue isn't deferred")
+	assert.False(t, app.scheduler.Pending("acme/api#3"), "the issue is closed")
+}
+
+func TestHandleIssueReopened(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		body     string
+		reminder string // start of the reminder after reopening, if any
+	}{
+		{name: "ignore", mode: config.ReopenedIgnore, body: "Login fails"},
+		{name: "check without estimate", mode: config.ReopenedCheck, body: "Login fails", reminder: "Hello!"},
+		{name: "check with estimate", mode: config.ReopenedCheck, body: "Estimate: 3 days"},
+		{name: "reestimate without estimate", mode: config.ReopenedReestimate, body: "Login fails", reminder: "Hello!"},
+		{
+			name:     "reestimate with estimate",
+			mode:     config.ReopenedReestimate,
+			body:     "Estimate: 3 days",
+			reminder: "Hello! This issue was reopened, so its estimate of 3 days may no longer hold.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, gh := newTestApp(t, map[string]string{"REOPENED_ISSUES": tt.mode})
+
+			require.NoError(t, app.HandleIssueReopened(issuesEvent("reopened", gh.issue(tt.body, "open"))))
+
+			found := reminders(gh)
+			if tt.reminder == "" {
+				assert.Empty(t, found)
+				return
+			}
+			require.Len(t, found, 1)
+			assert.True(t, strings.HasPrefix(found[0], reminderMarker+"\n"+tt.reminder), "reminder is %q", found[0])
+		})
+	}
+}
+
+func TestHandleIssueComment_EstimateEditedAway(t *testing.T) {
+	app, gh := newTestApp(t, nil)
+
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Login fails", "open"))))
+	comment := gh.addComment(1, "bob", "User", "MEMBER", "Estimate: 2 days")
+	require.NoError(t, app.HandleIssueComment(commentEvent("created", gh.issue("Login fails", "open"), comment)))
+	require.Empty(t, reminders(gh))
+
+	comment.Body = github.Ptr("Not sure about this one yet")
+	edited := commentEvent("edited", gh.issue("Login fails", "open"), comment)
+	edited.Changes = &github.EditChange{Body: &github.EditBody{From: github.Ptr("Estimate: 2 days")}}
+	require.NoError(t, app.HandleIssueComment(edited))
+	assert.Len(t, reminders(gh), 1, "removing the comment's estimate restores the reminder")
+}
+
+func TestHandleIssueComment_EstimateRevisesBody(t *testing.T) {
+	app, gh := newTestApp(t, nil)
+
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Estimate: 3 days", "open"))))
+	require.Empty(t, gh.bodies(1))
+
+	comment := gh.addComment(1, "bob", "User", "MEMBER", "Estimate: 2 days")
+	require.NoError(t, app.HandleIssueComment(commentEvent("created", gh.issue("Estimate: 3 days", "open"), comment)))
+	assert.Empty(t, reminders(gh), "the comment settles the estimate without asking which one counts")
+}
+
+func TestHandleIssueLabeled_ExemptLabel(t *testing.T) {
+	app, gh := newTestApp(t, nil)
+
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("How do I log in?", "open"))))
+	require.Len(t, reminders(gh), 1)
+
+	gh.labels[1] = []string{"question"}
+	labeled := issuesEvent("labeled", gh.issue("How do I log in?", "open"))
+	labeled.Label = &github.Label{Name: github.Ptr("question")}
+	require.NoError(t, app.HandleIssueLabeled(labeled))
+	assert.Empty(t, reminders(gh), "an exempt label retracts the reminder")
+
+	gh.labels[1] = nil
+	unlabeled := issuesEvent("unlabeled", gh.issue("How do I log in?", "open"))
+	unlabeled.Label = &github.Label{Name: github.Ptr("question")}
+	require.NoError(t, app.HandleIssueUnlabeled(unlabeled))
+	assert.Len(t, reminders(gh), 1, "removing the exempt label restores the reminder")
+}
+
+func TestHandleIssueLabeled_ByApp(t *testing.T) {
+	app, gh := newTestApp(t, nil)
+
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("How do I log in?", "open"))))
+	require.Len(t, reminders(gh), 1)
+
+	gh.labels[1] = []string{"question"}
+	labeled := issuesEvent("labeled", gh.issue("How do I log in?", "open"))
+	labeled.Label = &github.Label{Name: github.Ptr("question")}
+	labeled.Sender = &github.User{Login: github.Ptr(appLogin), Type: github.Ptr("Bot")}
+	require.NoError(t, app.HandleIssueLabeled(labeled))
+	assert.Len(t, reminders(gh), 1, "the command that added the label checks the issue")
+}
+
+func TestHandleIssueOpened_ExemptLabel(t *testing.T) {
+	app, gh := newTestApp(t, nil)
+	gh.labels[1] = []string{"Duplicate"}
+
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Login fails again", "open"))))
+	assert.Empty(t, gh.bodies(1))
+}
+
+func TestSplitByConfidence(t *testing.T) {
+	explicit := &estimate.Estimate{Text: "Estimate: 3 days", Confidence: 1}
+	// custom detectors may not set a confidence
+	custom := &estimate.Estimate{Text: "ETA: 2 days"}
+	likely := &estimate.Estimate{Text: "should take about 2 days", Natural: true, Confidence: 0.8}
+	unsure := &estimate.Estimate{Text: "2 days", Natural: true, Confidence: 0.4}
+	maybe := &estimate.Estimate{Text: "a couple of days", Natural: true, Confidence: 0.6}
+
+	accepted, tentative := splitByConfidence([]*estimate.Estimate{explicit, custom, unsure, likely, maybe}, 0.7)
+
+	if len(accepted) != 3 || accepted[0] != explicit || accepted[1] != custom || accepted[2] != likely {
+		t.Errorf("accepted = %v, expected the explicit, custom and likely estimates", accepted)
+	}
+	if len(tentative) != 2 || tentative[0] != maybe || tentative[1] != unsure {
+		t.Errorf("tentative = %v, expected the natural language estimates below 0.7, most confident first", tentative)
+	}
+}
@@ -0,0 +1,326 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/taman9333/issue-estimate-reminder/internal/commands"
+	"github.com/taman9333/issue-estimate-reminder/internal/config"
+	"github.com/taman9333/issue-estimate-reminder/internal/estimate"
+)
+
+// commandRequest is a slash command being run on an issue
+type commandRequest struct {
+	installationID int64
+	repo           *github.Repository
+	issue          *github.Issue
+	comment        *github.IssueComment
+	cmd            commands.Command
+}
+
+// usageError is a command mistake explained to the user in a reply
+type usageError struct {
+	reason string
+}
+
+func (e *usageError) Error() string {
+	return e.reason
+}
+
+func usage(format string, args ...any) *usageError {
+	return &usageError{reason: fmt.Sprintf(format, args...)}
+}
+
+// commandSpec describes a slash command, its role is configured per
+// repository in CommandRoles
+type commandSpec struct {
+	usage string
+	run   func(a *App, req *commandRequest) error
+}
+
+var commandSpecs = map[string]commandSpec{
+	"estimate": {
+		usage: "`/estimate <value>` such as `/estimate 3d`, or `/estimate clear`",
+		run:   (*App).runEstimate,
+	},
+	"no-estimate-needed": {
+		usage: "`/no-estimate-needed`",
+		run:   (*App).runNoEstimateNeeded,
+	},
+	"remind-me": {
+		usage: "`/remind-me in <delay>` such as `/remind-me in 2d`",
+		run:   (*App).runRemindMe,
+	},
+}
+
+// knownCommands drops the commands the app doesn't run, such as "/cc",
+// which are left to people and other apps. Likely typos of the app's own
+// commands are kept so runCommands can point out the right name.
+func knownCommands(cmds []commands.Command) []commands.Command {
+	return slices.DeleteFunc(cmds, func(cmd commands.Command) bool {
+		if _, ok := commandSpecs[cmd.Name]; ok {
+			return false
+		}
+		_, ok := closestCommand(cmd.Name)
+		return !ok
+	})
+}
+
+// closestCommand returns the command name is likely a typo of, one it is
+// a prefix of or the other way round, or one at most two edits away
+func closestCommand(name string) (string, bool) {
+	best, bestDistance := "", 3
+	for _, known := range slices.Sorted(maps.Keys(commandSpecs)) {
+		if len(name) >= 3 && (strings.HasPrefix(known, name) || strings.HasPrefix(name, known)) {
+			return known, true
+		}
+		if d := editDistance(name, known); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// runCommands runs the commands of a comment, acknowledging it with
+// a reaction if they all succeed and replying to any that fail
+func (a *App) runCommands(installationID int64, repo *github.Repository, issue *github.Issue, comment *github.IssueComment, cmds []commands.Command) error {
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	roles := a.config.ForRepo(repoFullName(repo)).CommandRoles
+	perms := newPermissions(client, repo, issue)
+	user := comment.GetUser()
+
+	ran, failed := 0, 0
+	for _, cmd := range cmds {
+		spec, ok := commandSpecs[cmd.Name]
+		if !ok {
+			name, _ := closestCommand(cmd.Name)
+			log.Printf("Unknown command %s on issue #%d, suggesting /%s", cmd, issue.GetNumber(), name)
+			failed++
+			if err := a.postComment(installationID, repo, issue.GetNumber(),
+				commandUnknownMessage(user.GetLogin(), cmd, name, commandSpecs[name].usage)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		role, ok := roles[cmd.Name]
+		if !ok {
+			role = config.RoleMaintainer
+		}
+		allowed, err := perms.has(user, comment.GetAuthorAssociation(), role)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			log.Printf("Denied %s on issue #%d to %s", cmd, issue.GetNumber(), user.GetLogin())
+			failed++
+			if err := a.postComment(installationID, repo, issue.GetNumber(),
+				commandDeniedMessage(user.GetLogin(), cmd, role)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		log.Printf("Running %s on issue #%d for %s", cmd, issue.GetNumber(), user.GetLogin())
+		req := &commandRequest{
+			installationID: installationID,
+			repo:           repo,
+			issue:          issue,
+			comment:        comment,
+			cmd:            cmd,
+		}
+		err = spec.run(a, req)
+		var usageErr *usageError
+		if errors.As(err, &usageErr) {
+			failed++
+			if err := a.postComment(installationID, repo, issue.GetNumber(),
+				commandErrorMessage(user.GetLogin(), cmd, usageErr.reason, spec.usage)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		ran++
+	}
+
+	if ran == 0 || failed > 0 {
+		return nil
+	}
+
+	_, _, err = client.Reactions.CreateIssueCommentReaction(context.Background(),
+		repo.GetOwner().GetLogin(), repo.GetName(), comment.GetID(), "+1")
+	if err != nil {
+		return fmt.Errorf("failed to add reaction: %v", err)
+	}
+	return nil
+}
+
+// runEstimate sets the issue's estimate label, or removes it for "clear"
+func (a *App) runEstimate(req *commandRequest) error {
+	parser := a.parserFor(req.repo)
+	args := req.cmd.Args
+	if args == "" {
+		return usage("it is missing a value")
+	}
+
+	var label string
+	if !strings.EqualFold(args, "clear") {
+		_, err := parser.ParseValue(args)
+		var invalidErr *estimate.InvalidError
+		switch {
+		case errors.As(err, &invalidErr):
+			return usage("`%s` is not accepted because %s", args, invalidErr.Reason)
+		case err != nil:
+			return usage("`%s` is not a %s estimate", args, parser.Name())
+		}
+
+		var ok bool
+		if label, ok = parser.EstimateLabel(args); !ok {
+			return usage("this repository has no estimate labels configured")
+		}
+	}
+
+	var labels []string
+	for _, name := range labelNames(req.issue) {
+		if !parser.IsEstimateLabel(name) {
+			labels = append(labels, name)
+			continue
+		}
+		if err := a.removeLabel(req.installationID, req.repo, req.issue.GetNumber(), name); err != nil {
+			return err
+		}
+	}
+	if label != "" {
+		if err := a.addLabel(req.installationID, req.repo, req.issue.GetNumber(), label); err != nil {
+			return err
+		}
+		labels = append(labels, label)
+	}
+
+	return a.checkIssue(req.installationID, req.repo, withLabels(req.issue, labels), false)
+}
+
+// runNoEstimateNeeded marks the issue as not needing an estimate
+func (a *App) runNoEstimateNeeded(req *commandRequest) error {
+	if req.cmd.Args != "" {
+		return usage("it takes no arguments")
+	}
+
+	label := a.config.ForRepo(repoFullName(req.repo)).NoEstimateLabel
+	if label == "" {
+		return usage("this repository has no label for issues without an estimate")
+	}
+
+	labels := labelNames(req.issue)
+	if !slices.Contains(labels, label) {
+		if err := a.addLabel(req.installationID, req.repo, req.issue.GetNumber(), label); err != nil {
+			return err
+		}
+		labels = append(labels, label)
+	}
+
+	return a.checkIssue(req.installationID, req.repo, withLabels(req.issue, labels), false)
+}
+
+// runRemindMe reminds the commenter about the issue after a delay unless
+// it has an estimate by then
+func (a *App) runRemindMe(req *commandRequest) error {
+	delay, err := commands.ParseDelay(req.cmd.Args)
+	if err != nil {
+		return usage("%v", err)
+	}
+
+	installationID, repo, number := req.installationID, req.repo, req.issue.GetNumber()
+	login := req.comment.GetUser().GetLogin()
+	a.scheduler.Schedule(userReminderKey(repo, number, login), delay, a.userReminderJob(installationID, repo, number, login))
+	log.Printf("Scheduled a reminder for %s on issue #%d in %v", login, number, delay)
+	return nil
+}
+
+// userReminderKey identifies a /remind-me job, e.g. "acme/api#12@octocat"
+func userReminderKey(repo *github.Repository, number int, login string) string {
+	return issueKey(repo, number) + "@" + login
+}
+
+func (a *App) userReminderJob(installationID int64, repo *github.Repository, number int, login string) func() {
+	return func() {
+		if a.suspended(installationID) {
+			return
+		}
+		if err := a.remindUser(installationID, repo, number, login); err != nil {
+			log.Printf("Error reminding %s about issue #%d: %v", login, number, err)
+		}
+	}
+}
+
+// remindUser mentions login on the issue if it is still open without an
+// estimate
+func (a *App) remindUser(installationID int64, repo *github.Repository, number int, login string) error {
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	issue, _, err := client.Issues.Get(context.Background(), repo.GetOwner().GetLogin(), repo.GetName(), number)
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %v", err)
+	}
+	if issue.GetState() == "closed" || a.isExempt(repo, issue) {
+		return nil
+	}
+
+	comments, err := a.estimateComments(installationID, repo, issue)
+	if err != nil {
+		return err
+	}
+	as := a.assess(repo, issue, comments)
+	if as.err == nil {
+		return nil
+	}
+
+	return a.postComment(installationID, repo, number, userReminderMessage(as.parser, login))
+}
+
+// withLabels returns a copy of issue with its labels replaced, for
+// checking it again right after changing them
+func withLabels(issue *github.Issue, names []string) *github.Issue {
+	updated := *issue
+	updated.Labels = make([]*github.Label, len(names))
+	for i, name := range names {
+		updated.Labels[i] = &github.Label{Name: github.Ptr(name)}
+	}
+	return &updated
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taman9333/issue-estimate-reminder/internal/commands"
+)
+
+func TestHandleIssueComment_EstimateWithOtherCommand(t *testing.T) {
+	app, gh := newTestApp(t, nil)
+
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Login fails", "open"))))
+	require.Len(t, reminders(gh), 1)
+
+	comment := gh.addComment(1, "bob", "User", "MEMBER", "Estimate: 2 days\n/cc @alice")
+	require.NoError(t, app.HandleIssueComment(commentEvent("created", gh.issue("Login fails", "open"), comment)))
+
+	assert.Empty(t, reminders(gh), "the comment's estimate resolves the reminder")
+	assert.Empty(t, gh.reactions, "/cc is not a command of the app")
+}
+
+func TestKnownCommands(t *testing.T) {
+	cmds := commands.Parse("/estimat 3d\n/cc @alice\n/remind-me in 2d\n/assign @bob\n/remind\n/no-estimates-needed")
+
+	var names []string
+	for _, cmd := range knownCommands(cmds) {
+		names = append(names, cmd.Name)
+	}
+	assert.Equal(t, []string{"estimat", "remind-me", "remind", "no-estimates-needed"}, names)
+}
+
+func TestRunCommands(t *testing.T) {
+	tests := []struct {
+		name        string
+		login       string
+		association string
+		role        string // repository role of login
+		body        string
+		reply       string // start of the app's reply, if any
+		reactions   []string
+		labels      []string
+		reminders   int
+	}{
+		{
+			name:        "maintainer exempts the issue",
+			login:       "carol",
+			association: "MEMBER",
+			role:        "maintain",
+			body:        "/no-estimate-needed",
+			reactions:   []string{"+1"},
+			labels:      []string{"no-estimate-needed"},
+		},
+		{
+			name:        "collaborator is denied a maintainer command",
+			login:       "bob",
+			association: "MEMBER",
+			role:        "write",
+			body:        "/no-estimate-needed",
+			reply:       "@bob only maintainers can use `/no-estimate-needed` in this repository.",
+			reminders:   1,
+		},
+		{
+			name:        "outsider is denied an author command",
+			login:       "mallory",
+			association: "NONE",
+			body:        "/remind-me in 2d",
+			reply:       "@mallory only the issue author, collaborators and maintainers can use `/remind-me`",
+			reminders:   1,
+		},
+		{
+			name:        "usage error",
+			login:       "alice",
+			association: "NONE",
+			body:        "/remind-me soon",
+			reply:       "@alice `/remind-me soon` didn't work because",
+			reminders:   1,
+		},
+		{
+			name:        "typo of a command",
+			login:       "alice",
+			association: "NONE",
+			body:        "/estimat 3d",
+			reply:       "@alice `/estimat` is not a command, did you mean `/estimate`?",
+			reminders:   1,
+		},
+		{
+			name:        "start of a command",
+			login:       "alice",
+			association: "NONE",
+			body:        "/no-estimate",
+			reply:       "@alice `/no-estimate` is not a command, did you mean `/no-estimate-needed`?",
+			reminders:   1,
+		},
+		{
+			name:        "author sets a reminder",
+			login:       "alice",
+			association: "NONE",
+			body:        "/remind-me in 2d",
+			reactions:   []string{"+1"},
+			reminders:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, gh := newTestApp(t, nil)
+			if tt.role != "" {
+				gh.roles[tt.login] = tt.role
+			}
+
+			require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Login fails", "open"))))
+			comment := gh.addComment(1, tt.login, "User", tt.association, tt.body)
+			require.NoError(t, app.HandleIssueComment(commentEvent("created", gh.issue("Login fails", "open"), comment)))
+
+			bodies := gh.bodies(1)
+			if tt.reply != "" {
+				assert.True(t, len(bodies) > 0 && strings.HasPrefix(bodies[len(bodies)-1], tt.reply),
+					"expected a reply starting with %q, comments are %q", tt.reply, bodies)
+			} else {
+				assert.Equal(t, tt.body, bodies[len(bodies)-1], "no reply expected")
+			}
+			assert.Equal(t, tt.reactions, gh.reactions)
+			assert.Equal(t, tt.labels, gh.issueLabels(1))
+			assert.Len(t, reminders(gh), tt.reminders)
+		})
+	}
+}
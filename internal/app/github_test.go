@@ -0,0 +1,311 @@
+package app
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v74/github"
+)
+
+// fakeGitHub serves the parts of the GitHub API the app uses for the
+// issues of one repository, keeping comments and labels in memory. Issues,
+// pull requests, timelines and events are set up by the tests.
+type fakeGitHub struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	nextID    int64
+	comments  map[int][]*github.IssueComment
+	labels    map[int][]string
+	reactions []string
+	issues    map[int]*github.Issue
+	pulls     map[int]*github.PullRequest
+	timelines map[int][]*github.Timeline
+	events    map[int][]*github.IssueEvent
+	checkRuns []github.CreateCheckRunOptions
+	roles     map[string]string // repository role by login
+}
+
+// appSlug names the app, its comments are posted as appLogin
+const (
+	appSlug  = "estimate-reminder"
+	appLogin = appSlug + "[bot]"
+)
+
+func newFakeGitHub(t *testing.T) *fakeGitHub {
+	f := &fakeGitHub{
+		comments:  map[int][]*github.IssueComment{},
+		labels:    map[int][]string{},
+		issues:    map[int]*github.Issue{},
+		pulls:     map[int]*github.PullRequest{},
+		timelines: map[int][]*github.Timeline{},
+		events:    map[int][]*github.IssueEvent{},
+		roles:     map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, &github.App{Slug: github.Ptr(appSlug)})
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}/comments", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		writeJSON(w, http.StatusOK, f.comments[number(r)])
+	})
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/comments", func(w http.ResponseWriter, r *http.Request) {
+		var comment github.IssueComment
+		if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, f.addComment(number(r), appLogin, "Bot", "NONE", comment.GetBody()))
+	})
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/comments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var edit github.IssueComment
+		if err := json.NewDecoder(r.Body).Decode(&edit); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		comment := f.comment(r)
+		if comment == nil {
+			http.NotFound(w, r)
+			return
+		}
+		comment.Body = edit.Body
+		writeJSON(w, http.StatusOK, comment)
+	})
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/issues/comments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		comment := f.comment(r)
+		if comment == nil {
+			http.NotFound(w, r)
+			return
+		}
+		for number, comments := range f.comments {
+			f.comments[number] = slices.DeleteFunc(comments, func(c *github.IssueComment) bool { return c == comment })
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/comments/{id}/reactions", func(w http.ResponseWriter, r *http.Request) {
+		var reaction github.Reaction
+		if err := json.NewDecoder(r.Body).Decode(&reaction); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.reactions = append(f.reactions, reaction.GetContent())
+		writeJSON(w, http.StatusCreated, reaction)
+	})
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/labels", func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		if err := json.NewDecoder(r.Body).Decode(&names); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for _, name := range names {
+			if !slices.Contains(f.labels[number(r)], name) {
+				f.labels[number(r)] = append(f.labels[number(r)], name)
+			}
+		}
+		writeJSON(w, http.StatusOK, labelsOf(f.labels[number(r)]))
+	})
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/issues/{number}/labels/{name}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		labels := f.labels[number(r)]
+		i := slices.Index(labels, r.PathValue("name"))
+		if i < 0 {
+			http.NotFound(w, r)
+			return
+		}
+		f.labels[number(r)] = slices.Delete(labels, i, i+1)
+		writeJSON(w, http.StatusOK, labelsOf(f.labels[number(r)]))
+	})
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		state := r.URL.Query().Get("state")
+		var labels []string
+		if query := r.URL.Query().Get("labels"); query != "" {
+			labels = strings.Split(query, ",")
+		}
+		issues := []*github.Issue{}
+		for _, n := range slices.Sorted(maps.Keys(f.issues)) {
+			issue := *f.issues[n]
+			issue.Labels = labelsOf(f.labels[n])
+			if state != "all" && issue.GetState() != cmp.Or(state, "open") {
+				continue
+			}
+			if !slices.ContainsFunc(labels, func(label string) bool { return !slices.Contains(f.labels[n], label) }) {
+				issues = append(issues, &issue)
+			}
+		}
+		writeJSON(w, http.StatusOK, issues)
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		issue, ok := f.issues[number(r)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, issue)
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}/timeline", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		writeJSON(w, http.StatusOK, f.timelines[number(r)])
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}/events", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		writeJSON(w, http.StatusOK, f.events[number(r)])
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		pr, ok := f.pulls[number(r)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, pr)
+	})
+	mux.HandleFunc("POST /repos/{owner}/{repo}/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		var opts github.CreateCheckRunOptions
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.checkRuns = append(f.checkRuns, opts)
+		writeJSON(w, http.StatusCreated, &github.CheckRun{Name: github.Ptr(opts.Name), HeadSHA: github.Ptr(opts.HeadSHA)})
+	})
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/collaborators/{login}/permission", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		role, ok := f.roles[r.PathValue("login")]
+		if !ok {
+			role = "read"
+		}
+		writeJSON(w, http.StatusOK, &github.RepositoryPermissionLevel{RoleName: github.Ptr(role)})
+	})
+
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeGitHub) CreateAppClient() (*github.Client, error) {
+	return f.client(), nil
+}
+
+func (f *fakeGitHub) CreateInstallationClient(int64) (*github.Client, error) {
+	return f.client(), nil
+}
+
+func (f *fakeGitHub) ForgetInstallation(int64) {}
+
+func (f *fakeGitHub) client() *github.Client {
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(f.server.URL + "/")
+	return client
+}
+
+// addComment adds a comment to an issue as if login wrote it
+func (f *fakeGitHub) addComment(number int, login, userType, association, body string) *github.IssueComment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.nextID++
+	comment := &github.IssueComment{
+		ID:                github.Ptr(f.nextID),
+		Body:              github.Ptr(body),
+		User:              &github.User{Login: github.Ptr(login), Type: github.Ptr(userType)},
+		AuthorAssociation: github.Ptr(association),
+	}
+	f.comments[number] = append(f.comments[number], comment)
+	return comment
+}
+
+// bodies returns the bodies of an issue's comments, oldest first
+func (f *fakeGitHub) bodies(number int) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var bodies []string
+	for _, comment := range f.comments[number] {
+		bodies = append(bodies, comment.GetBody())
+	}
+	return bodies
+}
+
+// conclusions returns the conclusions of the check runs published so far
+func (f *fakeGitHub) conclusions() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var conclusions []string
+	for _, run := range f.checkRuns {
+		conclusions = append(conclusions, run.GetConclusion())
+	}
+	return conclusions
+}
+
+func (f *fakeGitHub) issueLabels(number int) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.labels[number])
+}
+
+// comment returns the comment with the request's id, f.mu must be held
+func (f *fakeGitHub) comment(r *http.Request) *github.IssueComment {
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	for _, comments := range f.comments {
+		for _, comment := range comments {
+			if comment.GetID() == id {
+				return comment
+			}
+		}
+	}
+	return nil
+}
+
+func number(r *http.Request) int {
+	n, _ := strconv.Atoi(r.PathValue("number"))
+	return n
+}
+
+func labelsOf(names []string) []*github.Label {
+	labels := make([]*github.Label, len(names))
+	for i, name := range names {
+		labels[i] = &github.Label{Name: github.Ptr(name)}
+	}
+	return labels
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
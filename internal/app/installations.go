@@ -0,0 +1,262 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v74/github"
+)
+
+// HandleInstallation keeps track of the app being installed, uninstalled,
+// suspended or unsuspended on an account
+func (a *App) HandleInstallation(payload *github.InstallationEvent) error {
+	installation := payload.GetInstallation()
+	if installation == nil {
+		return fmt.Errorf("no installation found in payload")
+	}
+	id, account := installation.GetID(), installation.GetAccount().GetLogin()
+
+	switch payload.GetAction() {
+	case "created":
+		repos := repoNames(payload.Repositories)
+		a.installations.Add(id, account, repos)
+		log.Printf("Installed on %s with %d repositories", account, len(repos))
+		for _, name := range repos {
+			a.onboard(id, repoFromFullName(name))
+		}
+	case "deleted":
+		a.installations.Remove(id)
+		a.githubClient.ForgetInstallation(id)
+		log.Printf("Uninstalled from %s", account)
+	case "suspend", "unsuspend":
+		suspended := payload.GetAction() == "suspend"
+		if suspended {
+			a.githubClient.ForgetInstallation(id)
+		}
+		a.installations.AddRepos(id, account, repoNames(payload.Repositories))
+		a.installations.SetSuspended(id, suspended)
+		log.Printf("Installation on %s %sed", account, payload.GetAction())
+	}
+	return nil
+}
+
+// HandleInstallationRepositories keeps track of the repositories an
+// installation has access to
+func (a *App) HandleInstallationRepositories(payload *github.InstallationRepositoriesEvent) error {
+	installation := payload.GetInstallation()
+	if installation == nil {
+		return fmt.Errorf("no installation found in payload")
+	}
+	id, account := installation.GetID(), installation.GetAccount().GetLogin()
+
+	switch payload.GetAction() {
+	case "added":
+		repos := repoNames(payload.RepositoriesAdded)
+		a.installations.AddRepos(id, account, repos)
+		log.Printf("Added %d repositories to the installation on %s", len(repos), account)
+		for _, name := range repos {
+			a.onboard(id, repoFromFullName(name))
+		}
+	case "removed":
+		repos := repoNames(payload.RepositoriesRemoved)
+		a.installations.RemoveRepos(id, repos)
+		log.Printf("Removed %d repositories from the installation on %s", len(repos), account)
+	}
+	return nil
+}
+
+// SyncInstallations fills the installation registry from GitHub, so it is
+// complete without waiting for installation webhooks
+func (a *App) SyncInstallations() error {
+	appClient, err := a.githubClient.CreateAppClient()
+	if err != nil {
+		return fmt.Errorf("failed to create app client: %v", err)
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := appClient.Apps.ListInstallations(context.Background(), opts)
+		if err != nil {
+			return fmt.Errorf("failed to list installations: %v", err)
+		}
+		for _, installation := range page {
+			// a suspended installation can't get a token to list its
+			// repositories, the unsuspend event lists them
+			if installation.SuspendedAt != nil {
+				a.installations.Add(installation.GetID(), installation.GetAccount().GetLogin(), nil)
+				a.installations.SetSuspended(installation.GetID(), true)
+				continue
+			}
+
+			repos, err := a.installationRepos(installation.GetID())
+			if err != nil {
+				return err
+			}
+			a.installations.Add(installation.GetID(), installation.GetAccount().GetLogin(), repos)
+
+			// follow ups only live in memory, so they are rebuilt from the
+			// deferral labels after a restart
+			for _, name := range repos {
+				if err := a.restoreFollowUps(installation.GetID(), repoFromFullName(name)); err != nil {
+					log.Printf("Error restoring follow ups of %s: %v", name, err)
+				}
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	log.Printf("Synced %d installations", len(a.installations.List()))
+	return nil
+}
+
+// installationRepos lists the full names of the repositories an
+// installation has access to
+func (a *App) installationRepos(installationID int64) ([]string, error) {
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	var repos []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.Apps.ListRepos(context.Background(), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list installation repositories: %v", err)
+		}
+		repos = append(repos, repoNames(page.Repositories)...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return repos, nil
+}
+
+// repoScan is a repository waiting for its onboarding scan
+type repoScan struct {
+	installationID int64
+	repo           *github.Repository
+}
+
+// onboarding scans repositories one at a time in the background, so an
+// installation on a whole organization doesn't hit GitHub's rate limits
+type onboarding struct {
+	mu      sync.Mutex
+	queue   []repoScan
+	running bool
+}
+
+// onboard queues a scan of the open issues of a repository the app was
+// just given access to, if the repository has OnboardingScan set
+func (a *App) onboard(installationID int64, repo *github.Repository) {
+	if !a.config.ForRepo(repoFullName(repo)).OnboardingScan {
+		return
+	}
+
+	a.onboarding.mu.Lock()
+	defer a.onboarding.mu.Unlock()
+
+	a.onboarding.queue = append(a.onboarding.queue, repoScan{installationID: installationID, repo: repo})
+	if !a.onboarding.running {
+		a.onboarding.running = true
+		go a.runOnboarding()
+	}
+}
+
+// runOnboarding scans queued repositories until the queue is empty
+func (a *App) runOnboarding() {
+	for {
+		a.onboarding.mu.Lock()
+		if len(a.onboarding.queue) == 0 {
+			a.onboarding.running = false
+			a.onboarding.mu.Unlock()
+			return
+		}
+		scan := a.onboarding.queue[0]
+		a.onboarding.queue = a.onboarding.queue[1:]
+		a.onboarding.mu.Unlock()
+
+		if err := a.scanOpenIssues(scan.installationID, scan.repo); err != nil {
+			log.Printf("Error scanning open issues of %s: %v", repoFullName(scan.repo), err)
+		}
+	}
+}
+
+// scanOpenIssues checks every open issue of a repository for an estimate
+func (a *App) scanOpenIssues(installationID int64, repo *github.Repository) error {
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	checked := 0
+	opts := &github.IssueListByRepoOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		issues, resp, err := client.Issues.ListByRepo(context.Background(), repo.GetOwner().GetLogin(), repo.GetName(), opts)
+		if err != nil {
+			return fmt.Errorf("failed to list issues: %v", err)
+		}
+		for _, issue := range issues {
+			if issue.IsPullRequest() {
+				continue
+			}
+			// one failing issue shouldn't stop the others from being checked
+			if err := a.checkIssue(installationID, repo, issue, false); err != nil {
+				log.Printf("Error checking issue #%d of %s: %v", issue.GetNumber(), repoFullName(repo), err)
+			}
+			checked++
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.ListOptions.Page = resp.NextPage
+	}
+
+	log.Printf("Checked %d open issues of %s", checked, repoFullName(repo))
+	return nil
+}
+
+// suspended reports whether an installation is known to be suspended,
+// jobs scheduled before then would only fail
+func (a *App) suspended(installationID int64) bool {
+	inst, ok := a.installations.Get(installationID)
+	return ok && inst.Suspended
+}
+
+// installationFor returns the installation covering repo, or fallback
+// when the registry doesn't know it
+func (a *App) installationFor(repo *github.Repository, fallback int64) int64 {
+	if inst, ok := a.installations.ForRepo(repoFullName(repo)); ok {
+		return inst.ID
+	}
+	return fallback
+}
+
+func repoNames(repos []*github.Repository) []string {
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repo.GetFullName())
+	}
+	return names
+}
+
+// repoFromFullName builds a repository from "owner/repo", installation
+// payloads leave out the owner of their repositories
+func repoFromFullName(fullName string) *github.Repository {
+	owner, name, _ := strings.Cut(fullName, "/")
+	return &github.Repository{
+		Owner:    &github.User{Login: github.Ptr(owner)},
+		Name:     github.Ptr(name),
+		FullName: github.Ptr(fullName),
+	}
+}
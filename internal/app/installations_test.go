@@ -0,0 +1,65 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForOnboarding waits until the app has scanned every queued repository
+func waitForOnboarding(t *testing.T, app *App) {
+	require.Eventually(t, func() bool {
+		app.onboarding.mu.Lock()
+		defer app.onboarding.mu.Unlock()
+		return !app.onboarding.running
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func installationEvent(action string, repos ...*github.Repository) *github.InstallationEvent {
+	return &github.InstallationEvent{
+		Action: github.Ptr(action),
+		Installation: &github.Installation{
+			ID:      github.Ptr(int64(67890)),
+			Account: &github.User{Login: github.Ptr("acme")},
+		},
+		Repositories: repos,
+	}
+}
+
+func TestHandleInstallation_Onboarding(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		reminders int // reminders on issue 1, the open one without an estimate
+	}{
+		{name: "scan turned off"},
+		{
+			name:      "scan open issues",
+			env:       map[string]string{"ONBOARDING_SCAN": "true"},
+			reminders: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, gh := newTestApp(t, tt.env)
+			gh.issues[1] = gh.numberedIssue(1, "Login fails", "open")
+			gh.issues[2] = gh.numberedIssue(2, "Logout fails\n\nEstimate: 2 days", "open")
+			gh.issues[3] = gh.numberedIssue(3, "Signup fails", "closed")
+
+			require.NoError(t, app.HandleInstallation(installationEvent("created", testRepo)))
+			waitForOnboarding(t, app)
+
+			inst, ok := app.installations.ForRepo("acme/api")
+			require.True(t, ok)
+			assert.Equal(t, int64(67890), inst.ID)
+
+			assert.Len(t, reminders(gh), tt.reminders)
+			assert.Empty(t, gh.bodies(2), "the issue has an estimate")
+			assert.Empty(t, gh.bodies(3), "the issue is closed")
+		})
+	}
+}
@@ -7,5 +7,16 @@ import "github.com/google/go-github/v74/github"
 // AppInterface defines what handlers need from the app
 type AppInterface interface {
 	HandleIssueOpened(payload *github.IssuesEvent) error
+	HandleIssueEdited(payload *github.IssuesEvent) error
+	HandleIssueReopened(payload *github.IssuesEvent) error
+	HandleIssueTransferred(payload *IssueTransferredEvent) error
+	HandleIssueLabeled(payload *github.IssuesEvent) error
+	HandleIssueUnlabeled(payload *github.IssuesEvent) error
+	HandleIssueComment(payload *github.IssueCommentEvent) error
+	HandlePullRequest(payload *github.PullRequestEvent) error
+	HandleCheckRunRerequested(payload *github.CheckRunEvent) error
+	HandleInstallation(payload *github.InstallationEvent) error
+	HandleInstallationRepositories(payload *github.InstallationRepositoriesEvent) error
+	RequiredEvents() []string
 	GetWebhookSecret() string
 }
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/taman9333/issue-estimate-reminder/internal/commands"
+	"github.com/taman9333/issue-estimate-reminder/internal/config"
+	"github.com/taman9333/issue-estimate-reminder/internal/estimate"
+)
+
+// reminderMarker identifies the app's reminder comment on an issue so it
+// can be updated or resolved later
+const reminderMarker = "<!-- issue-estimate-reminder:reminder -->"
+
+// reminderMessage asks for an estimate in the format the parser accepts
+func reminderMessage(parser *estimate.Parser) string {
+	intro := fmt.Sprintf("Hello! Please add a %s estimate to this issue.", parser.Name())
+	return withFormatHelp(intro, parser)
+}
+
+// rejectionMessage explains why an estimate on the issue was not accepted
+func rejectionMessage(parser *estimate.Parser, invalidErr *estimate.InvalidError) string {
+	intro := fmt.Sprintf("Hello! The estimate `%s` on this issue was not accepted because %s.",
+		invalidErr.Text, invalidErr.Reason)
+	return withFormatHelp(intro, parser)
+}
+
+func withFormatHelp(intro string, parser *estimate.Parser) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", intro)
+	fmt.Fprintf(&b, "Format: %s\n\n", parser.Format())
+	fmt.Fprintf(&b, "Example: %s\n\n", parser.Example())
+	if hint := parser.Hint(); hint != "" {
+		fmt.Fprintf(&b, "%s\n\n", hint)
+	}
+	b.WriteString("Thanks!")
+	return b.String()
+}
+
+// reestimateMessage asks to confirm or revise the estimate of a reopened
+// issue
+func reestimateMessage(parser *estimate.Parser, est *estimate.Estimate) string {
+	intro := fmt.Sprintf("Hello! This issue was reopened, so its estimate of %s may no longer hold. "+
+		"Please check it and add a `Revised estimate: ...` if the remaining work differs.", est)
+	return withFormatHelp(intro, parser)
+}
+
+// userReminderMessage is the reminder asked for with /remind-me
+func userReminderMessage(parser *estimate.Parser, login string) string {
+	intro := fmt.Sprintf("@%s here is the reminder you asked for: this issue still needs a %s estimate.",
+		login, parser.Name())
+	return withFormatHelp(intro, parser)
+}
+
+// commandErrorMessage explains why a slash command could not run
+func commandErrorMessage(login string, cmd commands.Command, reason, usage string) string {
+	return fmt.Sprintf("@%s `%s` didn't work because %s.\n\nUsage: %s", login, cmd, reason, usage)
+}
+
+// commandUnknownMessage points out the command a mistyped one was meant
+// to be
+func commandUnknownMessage(login string, cmd commands.Command, name, usage string) string {
+	return fmt.Sprintf("@%s `/%s` is not a command, did you mean `/%s`?\n\nUsage: %s", login, cmd.Name, name, usage)
+}
+
+// commandDeniedMessage explains that a slash command needs a higher role
+func commandDeniedMessage(login string, cmd commands.Command, role string) string {
+	if role == config.RoleNone {
+		return fmt.Sprintf("@%s `/%s` is turned off in this repository.", login, cmd.Name)
+	}
+	return fmt.Sprintf("@%s only %s can use `/%s` in this repository.", login, roleNames[role], cmd.Name)
+}
+
+// roleNames describe who has a role
+var roleNames = map[string]string{
+	config.RoleAuthor:       "the issue author, collaborators and maintainers",
+	config.RoleCollaborator: "collaborators and maintainers",
+	config.RoleMaintainer:   "maintainers",
+}
+
+// checkRunOutput returns the title and summary of the estimate check run
+// for a pull request assessed as pr that closes the linked issues
+func checkRunOutput(pr *assessment, linked []linkedIssue, passed bool) (string, string) {
+	var b strings.Builder
+	if pr.err == nil {
+		fmt.Fprintf(&b, "The pull request has an estimate of %s.\n", pr.res.Estimate)
+	} else {
+		b.WriteString("The pull request has no estimate of its own.\n")
+	}
+
+	if len(linked) == 0 {
+		b.WriteString("\nIt doesn't close any issues. Link an estimated issue with a closing keyword such as `Fixes #12`, " +
+			"or add an estimate to the pull request.\n")
+	} else {
+		b.WriteString("\nIssues it closes:\n\n")
+		for _, issue := range linked {
+			mark := "❌"
+			if issue.ok {
+				mark = "✅"
+			}
+			fmt.Fprintf(&b, "- %s %s %s\n", mark, issue.ref, issue.status)
+		}
+	}
+
+	title := "Estimate missing"
+	if passed {
+		title = "Estimate present"
+	}
+	return title, b.String()
+}
+
+// resolvedMessage replaces a reminder once the issue is estimated
+func resolvedMessage(est *estimate.Estimate) string {
+	return fmt.Sprintf("Thanks! This issue now has an estimate of %s.", est)
+}
+
+// deferredMessage replaces a reminder once the estimate is put off
+const deferredMessage = "Thanks! The estimate for this issue has been put off for now."
+
+// exemptMessage replaces a reminder once the issue needs no estimate
+const exemptMessage = "Thanks! This issue doesn't need an estimate."
+
+// followUpMessage asks again for an estimate that was put off
+func followUpMessage(parser *estimate.Parser, days int64) string {
+	intro := fmt.Sprintf("Hello! The estimate for this issue was put off %d days ago. "+
+		"If it's clearer now, please add a %s estimate.", days, parser.Name())
+	return withFormatHelp(intro, parser)
+}
+
+// confirmationMessage asks whether a natural language phrase was meant as
+// the estimate
+func confirmationMessage(parser *estimate.Parser, est *estimate.Estimate) string {
+	intro := fmt.Sprintf("Hello! It sounds like \"%s\" (line %d) is the estimate for this issue, "+
+		"but I'm not sure. If it is, please write it as `Estimate: %s` so it counts.",
+		est.Text, est.Line, est.String())
+	return withFormatHelp(intro, parser)
+}
+
+// clarificationMessage lists conflicting estimates and says which one is used
+func clarificationMessage(res estimate.Resolution) string {
+	var b strings.Builder
+	b.WriteString("Hello! This issue has more than one estimate and they don't agree:\n\n")
+	for _, est := range res.All {
+		fmt.Fprintf(&b, "- `%s` (%s)\n", est.Text, describeSource(est))
+	}
+	b.WriteString("\n")
+
+	switch {
+	case !res.Explicit:
+		fmt.Fprintf(&b, "None of them is marked as revised, so `%s` is used for now. "+
+			"Please mark the current one as `Revised estimate: ...` or remove the others.\n\n", res.Estimate.Text)
+	case res.Estimate.Revised:
+		fmt.Fprintf(&b, "`%s` is used because it is marked as revised. "+
+			"If that's not right, please update or remove the others.\n\n", res.Estimate.Text)
+	default:
+		fmt.Fprintf(&b, "`%s` is used because it is the last one. "+
+			"If that's not right, please update or remove the others.\n\n", res.Estimate.Text)
+	}
+
+	b.WriteString("Thanks!")
+	return b.String()
+}
+
+// taskBreakdownMarker identifies the task breakdown comment so it is
+// updated instead of posted again
+const taskBreakdownMarker = "<!-- issue-estimate-reminder:task-breakdown -->"
+
+// taskBreakdownMessage lists the tasks of an issue with their estimates and
+// the total, totalErr is the reason the total was not accepted
+func taskBreakdownMessage(tasks *estimate.TaskList, totalErr error) string {
+	var b strings.Builder
+	b.WriteString("**Task estimates**\n\n")
+	b.WriteString("| Task | Estimate |\n|------|----------|\n")
+	for _, task := range tasks.Tasks {
+		text := strings.ReplaceAll(task.Text, "|", "\\|")
+		if task.Done {
+			text = "~~" + text + "~~"
+		}
+		value := "—"
+		if task.Estimate != nil {
+			value = task.Estimate.String()
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", text, value)
+	}
+	b.WriteString("\n")
+
+	var invalidErr *estimate.InvalidError
+	switch {
+	case errors.As(totalErr, &invalidErr):
+		fmt.Fprintf(&b, "**Total:** %s, which is not accepted because %s.\n", tasks.Total, invalidErr.Reason)
+	case tasks.Total != nil:
+		fmt.Fprintf(&b, "**Total:** %s\n", tasks.Total)
+	}
+	if missing := len(tasks.Tasks) - tasks.Estimated(); missing > 0 {
+		fmt.Fprintf(&b, "\n%d of %d tasks have no estimate yet.\n", missing, len(tasks.Tasks))
+	}
+	return b.String()
+}
+
+func describeSource(est *estimate.Estimate) string {
+	if est.Source == estimate.SourceBody || est.Source == estimate.SourceForm {
+		return fmt.Sprintf("%s, line %d", est.Source, est.Line)
+	}
+	return string(est.Source)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/taman9333/issue-estimate-reminder/internal/config"
+)
+
+// collaboratorAssociations are the author associations GitHub reports for
+// repository collaborators and organization members
+var collaboratorAssociations = []string{"OWNER", "MEMBER", "COLLABORATOR"}
+
+// maintainerRoles are the repository roles that count as maintainers
+var maintainerRoles = []string{"admin", "maintain"}
+
+// permissions checks users against a config role for one issue, caching
+// permission lookups
+type permissions struct {
+	client *github.Client
+	repo   *github.Repository
+	issue  *github.Issue
+	roles  map[string]string
+}
+
+func newPermissions(client *github.Client, repo *github.Repository, issue *github.Issue) *permissions {
+	return &permissions{client: client, repo: repo, issue: issue, roles: map[string]string{}}
+}
+
+// has reports whether user, who has the given author association with the
+// repository, has at least role
+func (p *permissions) has(user *github.User, association, role string) (bool, error) {
+	switch role {
+	case config.RoleNone:
+		return false, nil
+	case config.RoleAuthor:
+		if user.GetLogin() == p.issue.GetUser().GetLogin() {
+			return true, nil
+		}
+		fallthrough
+	case config.RoleCollaborator:
+		return slices.Contains(collaboratorAssociations, association), nil
+	case config.RoleMaintainer:
+		// maintainers are collaborators too, so only they need a lookup
+		if !slices.Contains(collaboratorAssociations, association) {
+			return false, nil
+		}
+		repoRole, err := p.repoRole(user.GetLogin())
+		if err != nil {
+			return false, err
+		}
+		return slices.Contains(maintainerRoles, repoRole), nil
+	}
+	return false, nil
+}
+
+// repoRole returns the user's role on the repository, e.g. "maintain"
+func (p *permissions) repoRole(login string) (string, error) {
+	if role, ok := p.roles[login]; ok {
+		return role, nil
+	}
+
+	level, _, err := p.client.Repositories.GetPermissionLevel(context.Background(),
+		p.repo.GetOwner().GetLogin(), p.repo.GetName(), login)
+	if err != nil {
+		return "", fmt.Errorf("failed to get permission level: %v", err)
+	}
+
+	p.roles[login] = level.GetRoleName()
+	return p.roles[login], nil
+}
@@ -0,0 +1,267 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/taman9333/issue-estimate-reminder/internal/utils"
+)
+
+// estimateCheckName is the check run published on pull requests, branch
+// protection can require it
+const estimateCheckName = "estimate present"
+
+// linkedIssue is an issue a pull request closes and whether it is estimated
+type linkedIssue struct {
+	ref    string // e.g. "#12" or "acme/api#12"
+	status string // e.g. "estimate of 3 days"
+	ok     bool
+}
+
+// HandlePullRequest publishes the estimate check run for a pull request.
+// It passes when the pull request has an estimate itself, or when every
+// issue it closes has one or doesn't need one.
+func (a *App) HandlePullRequest(payload *github.PullRequestEvent) error {
+	repo := payload.GetRepo()
+	installation := payload.GetInstallation()
+
+	if installation == nil {
+		return fmt.Errorf("no installation found in payload")
+	}
+	if !a.config.ForRepo(repoFullName(repo)).PullRequestCheck {
+		return nil
+	}
+
+	return a.publishEstimateCheck(installation.GetID(), repo, payload.GetPullRequest())
+}
+
+// HandleCheckRunRerequested publishes the estimate check run again when
+// it is re-run from the pull request, after a linked issue got its
+// estimate for example
+func (a *App) HandleCheckRunRerequested(payload *github.CheckRunEvent) error {
+	checkRun := payload.GetCheckRun()
+	repo := payload.GetRepo()
+	installation := payload.GetInstallation()
+
+	if installation == nil {
+		return fmt.Errorf("no installation found in payload")
+	}
+	if checkRun.GetName() != estimateCheckName || !a.config.ForRepo(repoFullName(repo)).PullRequestCheck {
+		return nil
+	}
+
+	client, err := a.githubClient.CreateInstallationClient(installation.GetID())
+	if err != nil {
+		return fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	ctx := context.Background()
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+
+	// check runs list only pull requests from the same repository, ones
+	// from forks are found by their head commit
+	numbers := make([]int, 0, len(checkRun.PullRequests))
+	for _, pr := range checkRun.PullRequests {
+		numbers = append(numbers, pr.GetNumber())
+	}
+	if len(numbers) == 0 {
+		prs, _, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, name, checkRun.GetHeadSHA(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to list pull requests: %v", err)
+		}
+		for _, pr := range prs {
+			if pr.GetHead().GetSHA() == checkRun.GetHeadSHA() {
+				numbers = append(numbers, pr.GetNumber())
+			}
+		}
+	}
+
+	for _, number := range numbers {
+		pr, _, err := client.PullRequests.Get(ctx, owner, name, number)
+		if err != nil {
+			return fmt.Errorf("failed to get pull request: %v", err)
+		}
+		if err := a.publishEstimateCheck(installation.GetID(), repo, pr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishEstimateCheck creates a completed estimate check run on the head
+// commit of pr
+func (a *App) publishEstimateCheck(installationID int64, repo *github.Repository, pr *github.PullRequest) error {
+	log.Printf("Checking pull request #%d: %s", pr.GetNumber(), pr.GetTitle())
+
+	prIssue := &github.Issue{Number: pr.Number, Title: pr.Title, Body: pr.Body, Labels: pr.Labels}
+	as := a.assess(repo, prIssue, nil)
+
+	var linked []linkedIssue
+	for _, ref := range utils.ClosingReferences(pr.GetBody()) {
+		linked = append(linked, a.checkLinkedIssue(installationID, repo, ref))
+	}
+
+	passed := as.err == nil
+	if !passed && len(linked) > 0 {
+		passed = true
+		for _, issue := range linked {
+			passed = passed && issue.ok
+		}
+	}
+
+	conclusion := "failure"
+	if passed {
+		conclusion = "success"
+	}
+	title, summary := checkRunOutput(as, linked, passed)
+
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	_, _, err = client.Checks.CreateCheckRun(context.Background(), repo.GetOwner().GetLogin(), repo.GetName(),
+		github.CreateCheckRunOptions{
+			Name:       estimateCheckName,
+			HeadSHA:    pr.GetHead().GetSHA(),
+			Status:     github.Ptr("completed"),
+			Conclusion: github.Ptr(conclusion),
+			Output: &github.CheckRunOutput{
+				Title:   github.Ptr(title),
+				Summary: github.Ptr(summary),
+			},
+		})
+	if err != nil {
+		return fmt.Errorf("failed to create check run: %v", err)
+	}
+
+	log.Printf("Published %s check on pull request #%d: %s", estimateCheckName, pr.GetNumber(), conclusion)
+	return nil
+}
+
+// checkLinkedIssue looks up whether an issue closed by a pull request in
+// repo has an estimate, using the settings of the issue's own repository
+func (a *App) checkLinkedIssue(installationID int64, repo *github.Repository, ref utils.IssueRef) linkedIssue {
+	issueRepo := repo
+	linked := linkedIssue{ref: fmt.Sprintf("#%d", ref.Number)}
+	if ref.Owner != "" {
+		issueRepo = repoFromFullName(ref.Owner + "/" + ref.Repo)
+		linked.ref = fmt.Sprintf("%s/%s#%d", ref.Owner, ref.Repo, ref.Number)
+		// the issue's repository may belong to another installation
+		installationID = a.installationFor(issueRepo, installationID)
+	}
+
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		log.Printf("Error reading linked issue %s: %v", linked.ref, err)
+		linked.status = "could not be read"
+		return linked
+	}
+
+	issue, _, err := client.Issues.Get(context.Background(), issueRepo.GetOwner().GetLogin(), issueRepo.GetName(), ref.Number)
+	if err != nil {
+		log.Printf("Error reading linked issue %s: %v", linked.ref, err)
+		linked.status = "could not be read"
+		return linked
+	}
+	if issue.IsPullRequest() {
+		linked.status = "is a pull request, not an issue"
+		return linked
+	}
+	if a.isExempt(issueRepo, issue) {
+		linked.status, linked.ok = "doesn't need an estimate", true
+		return linked
+	}
+
+	comments, err := a.estimateComments(installationID, issueRepo, issue)
+	if err != nil {
+		log.Printf("Error reading comments of linked issue %s: %v", linked.ref, err)
+	}
+	as := a.assess(issueRepo, issue, comments)
+	if as.err != nil {
+		linked.status = "has no estimate"
+		return linked
+	}
+
+	linked.status, linked.ok = fmt.Sprintf("estimate of %s", as.res.Estimate), true
+	return linked
+}
+
+// refreshEstimateChecks publishes the estimate check run again on the open
+// pull requests that close an issue, found from the issue's cross
+// references, so they follow the issue getting or losing its estimate
+func (a *App) refreshEstimateChecks(installationID int64, repo *github.Repository, number int) error {
+	if !a.checksPullRequests() {
+		return nil
+	}
+
+	client, err := a.githubClient.CreateInstallationClient(installationID)
+	if err != nil {
+		return fmt.Errorf("failed to create installation client: %v", err)
+	}
+
+	ctx := context.Background()
+	var prs []*github.Issue
+	seen := map[int64]bool{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		events, resp, err := client.Issues.ListIssueTimeline(ctx, repo.GetOwner().GetLogin(), repo.GetName(), number, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list issue timeline: %v", err)
+		}
+		for _, event := range events {
+			source := event.GetSource().GetIssue()
+			if event.GetEvent() != "cross-referenced" || !source.IsPullRequest() || source.GetState() != "open" {
+				continue
+			}
+			if !seen[source.GetID()] && closes(source, repo, number) {
+				seen[source.GetID()] = true
+				prs = append(prs, source)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	for _, source := range prs {
+		prRepo := source.GetRepository()
+		if !a.config.ForRepo(repoFullName(prRepo)).PullRequestCheck {
+			continue
+		}
+
+		// the pull request's repository may belong to another installation
+		prInstallationID := a.installationFor(prRepo, installationID)
+		prClient, err := a.githubClient.CreateInstallationClient(prInstallationID)
+		if err != nil {
+			return fmt.Errorf("failed to create installation client: %v", err)
+		}
+		pr, _, err := prClient.PullRequests.Get(ctx, prRepo.GetOwner().GetLogin(), prRepo.GetName(), source.GetNumber())
+		if err != nil {
+			return fmt.Errorf("failed to get pull request: %v", err)
+		}
+		if err := a.publishEstimateCheck(prInstallationID, prRepo, pr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closes reports whether the pull request behind source says it closes
+// issue number of repo
+func closes(source *github.Issue, repo *github.Repository, number int) bool {
+	for _, ref := range utils.ClosingReferences(source.GetBody()) {
+		refRepo := source.GetRepository()
+		if ref.Owner != "" {
+			refRepo = repoFromFullName(ref.Owner + "/" + ref.Repo)
+		}
+		if ref.Number == number && strings.EqualFold(repoFullName(refRepo), repoFullName(repo)) {
+			return true
+		}
+	}
+	return false
+}
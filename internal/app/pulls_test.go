@@ -0,0 +1,170 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openPullRequest sets up pull request 2 of testRepo with body, and the
+// cross reference it leaves on the timeline of issue 1
+func (f *fakeGitHub) openPullRequest(body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.pulls[2] = &github.PullRequest{
+		Number: github.Ptr(2),
+		Title:  github.Ptr("Fix the login redirect"),
+		Body:   github.Ptr(body),
+		State:  github.Ptr("open"),
+		Head:   &github.PullRequestBranch{SHA: github.Ptr("abc123")},
+	}
+	f.timelines[1] = append(f.timelines[1], &github.Timeline{
+		Event: github.Ptr("cross-referenced"),
+		Source: &github.Source{Issue: &github.Issue{
+			ID:               github.Ptr(int64(2)),
+			Number:           github.Ptr(2),
+			Body:             github.Ptr(body),
+			State:            github.Ptr("open"),
+			Repository:       testRepo,
+			PullRequestLinks: &github.PullRequestLinks{},
+		}},
+	})
+}
+
+func TestCheckIssue_RefreshesEstimateChecks(t *testing.T) {
+	app, gh := newTestApp(t, map[string]string{"PULL_REQUEST_CHECK": "true"})
+	gh.openPullRequest("Fixes #1")
+
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Login fails", "open"))))
+	require.Len(t, reminders(gh), 1)
+
+	gh.issues[1] = gh.issue("Login fails\n\nEstimate: 3 days", "open")
+	require.NoError(t, app.HandleIssueEdited(issuesEvent("edited", gh.issues[1])))
+	assert.Equal(t, []string{"success"}, gh.conclusions(), "resolving the reminder passes the check")
+
+	require.NoError(t, app.HandleIssueEdited(issuesEvent("edited", gh.issues[1])))
+	assert.Equal(t, []string{"success"}, gh.conclusions(), "an unchanged reminder leaves the check alone")
+
+	gh.issues[1] = gh.issue("Login fails", "open")
+	require.NoError(t, app.HandleIssueEdited(issuesEvent("edited", gh.issues[1])))
+	assert.Equal(t, []string{"success", "failure"}, gh.conclusions(), "restoring the reminder fails the check")
+}
+
+func TestCheckIssue_IgnoresMentioningPullRequests(t *testing.T) {
+	app, gh := newTestApp(t, map[string]string{"PULL_REQUEST_CHECK": "true"})
+	gh.openPullRequest("Related to #1")
+
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Login fails", "open"))))
+	gh.issues[1] = gh.issue("Login fails\n\nEstimate: 3 days", "open")
+	require.NoError(t, app.HandleIssueEdited(issuesEvent("edited", gh.issues[1])))
+
+	assert.Empty(t, gh.conclusions(), "the pull request doesn't close the issue")
+}
+
+func TestHandlePullRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		body       string
+		issue      string // body of issue 1, if it exists
+		labels     []string
+		conclusion []string
+		summary    string
+	}{
+		{
+			name:       "estimate in the pull request",
+			body:       "Estimate: 1 day",
+			conclusion: []string{"success"},
+			summary:    "The pull request has an estimate of 1 days.",
+		},
+		{
+			name:       "closes an estimated issue",
+			body:       "Fixes #1",
+			issue:      "Login fails\n\nEstimate: 3 days",
+			conclusion: []string{"success"},
+			summary:    "- ✅ #1 estimate of 3 days",
+		},
+		{
+			name:       "closes an exempt issue",
+			body:       "Fixes #1",
+			issue:      "How do I log in?",
+			labels:     []string{"question"},
+			conclusion: []string{"success"},
+			summary:    "- ✅ #1 doesn't need an estimate",
+		},
+		{
+			name:       "closes an issue without an estimate",
+			body:       "Fixes #1",
+			issue:      "Login fails",
+			conclusion: []string{"failure"},
+			summary:    "- ❌ #1 has no estimate",
+		},
+		{
+			name:       "closes a missing issue",
+			body:       "Fixes #1",
+			conclusion: []string{"failure"},
+			summary:    "- ❌ #1 could not be read",
+		},
+		{
+			name:       "closes nothing",
+			body:       "Small cleanup",
+			conclusion: []string{"failure"},
+			summary:    "It doesn't close any issues.",
+		},
+		{
+			name: "check turned off",
+			env:  map[string]string{"PULL_REQUEST_CHECK": "false"},
+			body: "Estimate: 1 day",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{"PULL_REQUEST_CHECK": "true"}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			app, gh := newTestApp(t, env)
+			gh.labels[1] = tt.labels
+			if tt.issue != "" {
+				gh.issues[1] = gh.issue(tt.issue, "open")
+			}
+			gh.openPullRequest(tt.body)
+
+			require.NoError(t, app.HandlePullRequest(&github.PullRequestEvent{
+				Action:       github.Ptr("opened"),
+				PullRequest:  gh.pulls[2],
+				Repo:         testRepo,
+				Installation: &github.Installation{ID: github.Ptr(int64(67890))},
+			}))
+
+			assert.Equal(t, tt.conclusion, gh.conclusions())
+			if tt.summary != "" {
+				require.Len(t, gh.checkRuns, 1)
+				assert.Equal(t, "abc123", gh.checkRuns[0].HeadSHA)
+				assert.Contains(t, gh.checkRuns[0].GetOutput().GetSummary(), tt.summary)
+			}
+		})
+	}
+}
+
+func TestHandleCheckRunRerequested(t *testing.T) {
+	app, gh := newTestApp(t, map[string]string{"PULL_REQUEST_CHECK": "true"})
+	gh.issues[1] = gh.issue("Login fails\n\nEstimate: 3 days", "open")
+	gh.openPullRequest("Fixes #1")
+
+	require.NoError(t, app.HandleCheckRunRerequested(&github.CheckRunEvent{
+		Action: github.Ptr("rerequested"),
+		CheckRun: &github.CheckRun{
+			Name:         github.Ptr(estimateCheckName),
+			HeadSHA:      github.Ptr("abc123"),
+			PullRequests: []*github.PullRequest{{Number: github.Ptr(2)}},
+		},
+		Repo:         testRepo,
+		Installation: &github.Installation{ID: github.Ptr(int64(67890))},
+	}))
+	assert.Equal(t, []string{"success"}, gh.conclusions())
+}
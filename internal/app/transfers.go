@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+)
+
+// IssueTransferredEvent is an issues event with the "transferred" action.
+// go-github's EditChange has no fields for where the issue went, so the
+// changes are decoded here.
+type IssueTransferredEvent struct {
+	Action  string               `json:"action"`
+	Issue   *github.Issue        `json:"issue"`
+	Changes *IssueTransferChange `json:"changes"`
+	// Repo is the repository the issue was transferred from
+	Repo         *github.Repository   `json:"repository"`
+	Installation *github.Installation `json:"installation"`
+}
+
+// IssueTransferChange is the issue as it is in its new repository
+type IssueTransferChange struct {
+	NewIssue      *github.Issue      `json:"new_issue"`
+	NewRepository *github.Repository `json:"new_repository"`
+}
+
+// HandleIssueTransferred moves an issue's pending follow ups to its new
+// repository and checks it again with that repository's settings. The
+// reminder comment moves with the issue, so it is updated or resolved in
+// place.
+func (a *App) HandleIssueTransferred(payload *IssueTransferredEvent) error {
+	if payload.Installation == nil {
+		return fmt.Errorf("no installation found in payload")
+	}
+	if payload.Changes == nil || payload.Changes.NewIssue == nil || payload.Changes.NewRepository == nil {
+		return fmt.Errorf("no new issue found in payload")
+	}
+
+	oldRepo, oldNumber := payload.Repo, payload.Issue.GetNumber()
+	issue, repo := payload.Changes.NewIssue, payload.Changes.NewRepository
+	if repo.GetOwner() == nil {
+		repo = repoFromFullName(repo.GetFullName())
+	}
+
+	// the new repository may belong to another installation of the app
+	installationID := a.installationFor(repo, payload.Installation.GetID())
+
+	oldKey, newKey := issueKey(oldRepo, oldNumber), issueKey(repo, issue.GetNumber())
+	log.Printf("Issue %s was transferred to %s", oldKey, newKey)
+
+	// the deferral follow up keeps its time unless the new repository
+	// doesn't follow up at all
+	if a.config.ForRepo(repoFullName(repo)).DeferralFollowUpDays > 0 {
+		if a.scheduler.Move(oldKey, newKey, a.followUpJob(installationID, repo, issue.GetNumber())) {
+			log.Printf("Moved the follow up to %s", newKey)
+		}
+	} else if a.scheduler.Cancel(oldKey) {
+		log.Printf("Cancelled the follow up on issue #%d", oldNumber)
+	}
+	prefix := oldKey + "@"
+	for _, key := range a.scheduler.Keys(prefix) {
+		login := strings.TrimPrefix(key, prefix)
+		a.scheduler.Move(key, userReminderKey(repo, issue.GetNumber(), login),
+			a.userReminderJob(installationID, repo, issue.GetNumber(), login))
+		log.Printf("Moved the reminder for %s to %s", login, newKey)
+	}
+
+	if issue.GetState() == "closed" {
+		return nil
+	}
+
+	// a deferral label carried over from the old repository's settings
+	// is replaced by the new repository's own
+	oldLabel := a.config.ForRepo(repoFullName(oldRepo)).DeferralLabel
+	newLabel := a.config.ForRepo(repoFullName(repo)).DeferralLabel
+	labels := labelNames(issue)
+	if oldLabel != "" && oldLabel != newLabel && slices.Contains(labels, oldLabel) {
+		if err := a.removeLabel(installationID, repo, issue.GetNumber(), oldLabel); err != nil {
+			return err
+		}
+		issue = withLabels(issue, slices.DeleteFunc(labels, func(name string) bool { return name == oldLabel }))
+	}
+
+	return a.checkIssue(installationID, repo, issue, false)
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var otherRepo = &github.Repository{
+	Owner:    &github.User{Login: github.Ptr("acme")},
+	Name:     github.Ptr("web"),
+	FullName: github.Ptr("acme/web"),
+}
+
+// repoConfigFile writes a REPO_CONFIG_PATH file with content
+func repoConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "repos.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// transferEvent moves issue 1 of testRepo to issue 1 of otherRepo, the fake
+// keeps its comments and labels
+func transferEvent(issue *github.Issue) *IssueTransferredEvent {
+	return &IssueTransferredEvent{
+		Action:       "transferred",
+		Issue:        issue,
+		Changes:      &IssueTransferChange{NewIssue: issue, NewRepository: otherRepo},
+		Repo:         testRepo,
+		Installation: &github.Installation{ID: github.Ptr(int64(67890))},
+	}
+}
+
+func TestHandleIssueTransferred(t *testing.T) {
+	tests := []struct {
+		name     string
+		repos    string // repository config of acme/web
+		followUp bool   // the follow up moved to the new issue
+		labels   []string
+	}{
+		{
+			name:     "same settings",
+			followUp: true,
+			labels:   []string{"needs-estimate"},
+		},
+		{
+			name:   "new repository without follow ups",
+			repos:  `{"acme/web": {"deferral_label": "estimate-later", "deferral_follow_up_days": 0}}`,
+			labels: []string{"estimate-later"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			if tt.repos != "" {
+				env["REPO_CONFIG_PATH"] = repoConfigFile(t, tt.repos)
+			}
+			app, gh := newTestApp(t, env)
+
+			require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Estimate: TBD", "open"))))
+			require.True(t, app.scheduler.Pending("acme/api#1"))
+			app.scheduler.Schedule(userReminderKey(testRepo, 1, "alice"), time.Hour, func() {})
+
+			require.NoError(t, app.HandleIssueTransferred(transferEvent(gh.issue("Estimate: TBD", "open"))))
+
+			assert.False(t, app.scheduler.Pending("acme/api#1"))
+			assert.Equal(t, tt.followUp, app.scheduler.Pending("acme/web#1"))
+			assert.False(t, app.scheduler.Pending("acme/api#1@alice"))
+			assert.True(t, app.scheduler.Pending("acme/web#1@alice"), "/remind-me moves with the issue")
+			assert.Equal(t, tt.labels, gh.issueLabels(1))
+		})
+	}
+}
+
+func TestHandleIssueTransferred_ResolvesReminder(t *testing.T) {
+	env := map[string]string{"REPO_CONFIG_PATH": repoConfigFile(t, `{"acme/web": {"exempt_labels": ["triage"]}}`)}
+	app, gh := newTestApp(t, env)
+
+	require.NoError(t, app.HandleIssueOpened(issuesEvent("opened", gh.issue("Login fails", "open"))))
+	require.Len(t, reminders(gh), 1)
+
+	gh.labels[1] = []string{"triage"}
+	require.NoError(t, app.HandleIssueTransferred(transferEvent(gh.issue("Login fails", "open"))))
+	assert.Empty(t, reminders(gh), "the new repository's settings exempt the issue")
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taman9333/issue-estimate-reminder/internal/estimate"
+)
+
+// Command is a slash command written at the start of a line of a comment,
+// e.g. "/estimate 3d"
+type Command struct {
+	Name string // lowercased name without the slash, e.g. "estimate"
+	Args string // rest of the line, e.g. "3d"
+	Line int    // 1-based line number of the command
+}
+
+// String formats the command as it is typed
+func (c Command) String() string {
+	if c.Args == "" {
+		return "/" + c.Name
+	}
+	return "/" + c.Name + " " + c.Args
+}
+
+// matches a command on its own line such as "/remind-me in 2d"
+var commandPattern = regexp.MustCompile(`(?m)^[ \t]*/([a-zA-Z][a-zA-Z0-9-]*)(?:[ \t]+(.*?))?[ \t\r]*$`)
+
+// Parse returns the commands in the visible text of a comment in the order
+// they are written, commands in code or quoted replies are ignored
+func Parse(body string) []Command {
+	body = estimate.VisibleText(body)
+
+	var cmds []Command
+	for _, m := range commandPattern.FindAllStringSubmatchIndex(body, -1) {
+		cmd := Command{
+			Name: strings.ToLower(body[m[2]:m[3]]),
+			Line: strings.Count(body[:m[0]], "\n") + 1,
+		}
+		if m[4] >= 0 {
+			cmd.Args = body[m[4]:m[5]]
+		}
+		cmds = append(cmds, cmd)
+	}
+	return cmds
+}
+
+var (
+	// matches a delay such as "in 2d", "3 hours" or "in 1 week"
+	delayPattern = regexp.MustCompile(`(?i)^(?:in\s+)?(\d+)\s*([a-z]+)$`)
+
+	delayUnits = map[string]time.Duration{
+		"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+		"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+		"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+	}
+)
+
+// ParseDelay parses a calendar delay such as "in 2d" or "in 3 hours"
+func ParseDelay(s string) (time.Duration, error) {
+	m := delayPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("%q is not a delay such as \"in 2d\"", s)
+	}
+
+	unit, ok := delayUnits[strings.ToLower(m[2])]
+	if !ok {
+		return 0, fmt.Errorf("%q is not a known unit, use minutes, hours, days or weeks", m[2])
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("the delay must be more than zero")
+	}
+	return time.Duration(n) * unit, nil
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	body := "Thanks!\n/estimate 3d\n  /No-Estimate-Needed\n```\n/estimate 5d\n```\n> /remind-me in 2d\nsee /estimate docs"
+
+	cmds := Parse(body)
+
+	expected := []Command{
+		{Name: "estimate", Args: "3d", Line: 2},
+		{Name: "no-estimate-needed", Line: 3},
+	}
+	if len(cmds) != len(expected) {
+		t.Fatalf("Parse() found %d commands, expected %d: %v", len(cmds), len(expected), cmds)
+	}
+	for i, cmd := range cmds {
+		if cmd != expected[i] {
+			t.Errorf("command %d = %+v, expected %+v", i, cmd, expected[i])
+		}
+	}
+}
+
+func TestParseDelay(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{input: "in 2d", expected: 48 * time.Hour},
+		{input: "in 3 hours", expected: 3 * time.Hour},
+		{input: "1 week", expected: 7 * 24 * time.Hour},
+		{input: "IN 30 MIN", expected: 30 * time.Minute},
+		{input: "in 0d", wantErr: true},
+		{input: "in 2 fortnights", wantErr: true},
+		{input: "tomorrow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			delay, err := ParseDelay(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDelay(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if delay != tt.expected {
+				t.Errorf("ParseDelay(%q) = %v, expected %v", tt.input, delay, tt.expected)
+			}
+		})
+	}
+}
@@ -1,12 +1,18 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/taman9333/issue-estimate-reminder/internal/estimate"
 )
 
 type Config struct {
@@ -14,19 +20,158 @@ type Config struct {
 	PrivateKeyPath string
 	WebhookSecret  string
 	Port           string
+	WorkdayLength  time.Duration
+	SprintDays     int64
+
+	// Defaults apply to every repository without an entry in Repos
+	Defaults RepoConfig
+	// Repos holds per repository settings keyed by lowercase "owner/repo"
+	Repos map[string]RepoConfig
+}
+
+// RepoConfig holds the settings that can differ between repositories.
+// Entries in the REPO_CONFIG_PATH file only need the fields they change,
+// everything else is taken from Defaults.
+type RepoConfig struct {
+	Scheme      estimate.Scheme `json:"scheme"`
+	StoryPoints []float64       `json:"story_points"`
+	TShirtSizes []string        `json:"tshirt_sizes"`
+	// LabelPatterns mark labels carrying an estimate, "*" is the value
+	LabelPatterns []string `json:"label_patterns"`
+	// TitlePatterns mark an estimate in the issue title, "*" is the value
+	TitlePatterns []string `json:"title_patterns"`
+	// FrontMatterField is the front matter key holding the estimate
+	FrontMatterField string `json:"front_matter_field"`
+	// FormHeading is the issue form field holding the estimate
+	FormHeading string `json:"form_heading"`
+	// Locales whose estimate keywords, units and number formats are accepted
+	Locales []string `json:"locales"`
+	// MinEstimate and MaxEstimate bound accepted time estimates, written
+	// like an estimate value such as "1h" or "6 months", empty means no bound
+	MinEstimate string `json:"min_estimate"`
+	MaxEstimate string `json:"max_estimate"`
+	// ConflictRule picks the estimate that counts when several disagree
+	ConflictRule estimate.ConflictRule `json:"conflict_rule"`
+	// Detectors lists the estimate detectors to run, in order
+	Detectors []string `json:"detectors"`
+	// MinConfidence is the confidence a natural language estimate needs to
+	// be accepted, below it the app asks for confirmation
+	MinConfidence float64 `json:"min_confidence"`
+	// DeferralTokens are values putting the estimate off, e.g. "TBD"
+	DeferralTokens []string `json:"deferral_tokens"`
+	// DeferralLabel is added to issues whose estimate is deferred
+	DeferralLabel string `json:"deferral_label"`
+	// DeferralFollowUpDays is when to check a deferred issue again, 0 never
+	DeferralFollowUpDays int64 `json:"deferral_follow_up_days"`
+	// ResolvedReminders is what happens to a reminder once the issue has an
+	// estimate, ResolvedDelete or ResolvedUpdate
+	ResolvedReminders string `json:"resolved_reminders"`
+	// ReopenedIssues is how reopened issues are handled, one of
+	// ReopenedIgnore, ReopenedCheck or ReopenedReestimate
+	ReopenedIssues string `json:"reopened_issues"`
+	// CommentEstimateRole is who may give the estimate in a comment, one of
+	// the Role constants
+	CommentEstimateRole string `json:"comment_estimate_role"`
+	// CommandRoles is who may use each slash command, keyed by command name
+	// without the slash
+	CommandRoles map[string]string `json:"command_roles"`
+	// NoEstimateLabel marks issues that don't need an estimate, it is added
+	// by the /no-estimate-needed command
+	NoEstimateLabel string `json:"no_estimate_label"`
+	// ExemptLabels mark issues that don't need an estimate either, such as
+	// questions and duplicates
+	ExemptLabels []string `json:"exempt_labels"`
+	// PullRequestCheck publishes a check run on pull requests that passes
+	// when they or the issues they close are estimated
+	PullRequestCheck bool `json:"pull_request_check"`
+	// TaskBreakdown posts a comment listing the task list estimates and
+	// their total
+	TaskBreakdown bool `json:"task_breakdown"`
+	// OnboardingScan checks the open issues of a repository when the app
+	// is given access to it
+	OnboardingScan bool `json:"onboarding_scan"`
 }
 
+// What happens to a reminder comment once the issue has an estimate
+const (
+	ResolvedDelete = "delete" // the reminder is deleted
+	ResolvedUpdate = "update" // the reminder is replaced with a thank you
+)
+
+// How reopened issues are handled
+const (
+	ReopenedIgnore     = "ignore"     // nothing happens
+	ReopenedCheck      = "check"      // checked for an estimate like a new issue
+	ReopenedReestimate = "reestimate" // asked to confirm or revise the estimate
+)
+
+// Roles a user can have on an issue, from least to most trusted, each role
+// includes the ones before it
+const (
+	RoleNone         = "none"         // nobody
+	RoleAuthor       = "author"       // the issue author
+	RoleCollaborator = "collaborator" // repository collaborators and organization members
+	RoleMaintainer   = "maintainer"   // users with maintain or admin permission
+)
+
+// DefaultCommandRoles let issue authors give their estimate and set
+// reminders, and leave exempting an issue to maintainers
+var DefaultCommandRoles = map[string]string{
+	"estimate":           RoleAuthor,
+	"no-estimate-needed": RoleMaintainer,
+	"remind-me":          RoleAuthor,
+}
+
+// DefaultExemptLabels are GitHub's default labels for issues that won't
+// be worked on
+var DefaultExemptLabels = []string{"question", "duplicate", "wontfix"}
+
 func Load() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system environment variables")
 	}
 
 	config := &Config{
-		AppID:          getEnvAsInt("GITHUB_APP_ID"),
+		AppID:          getEnvAsInt("GITHUB_APP_ID", 0),
 		PrivateKeyPath: getEnv("GITHUB_PRIVATE_KEY_PATH", "./app.pem"),
 		WebhookSecret:  getEnv("WEBHOOK_SECRET", ""),
 		Port:           getEnv("PORT", "8080"),
+		WorkdayLength:  getEnvAsDuration("WORKDAY_LENGTH", 8*time.Hour),
+		SprintDays:     getEnvAsInt("SPRINT_LENGTH_DAYS", 10),
+		Defaults: RepoConfig{
+			Scheme:               estimate.Scheme(getEnv("ESTIMATE_SCHEME", string(estimate.SchemeTime))),
+			StoryPoints:          getEnvAsFloatList("STORY_POINTS", estimate.DefaultPoints),
+			TShirtSizes:          getEnvAsList("TSHIRT_SIZES", estimate.DefaultSizes),
+			LabelPatterns:        getEnvAsList("ESTIMATE_LABEL_PATTERNS", estimate.DefaultLabelPatterns),
+			TitlePatterns:        getEnvAsList("ESTIMATE_TITLE_PATTERNS", nil),
+			FormHeading:          getEnv("ESTIMATE_FORM_HEADING", estimate.DefaultFormHeading),
+			FrontMatterField:     getEnv("ESTIMATE_FRONT_MATTER_FIELD", estimate.DefaultFrontMatterField),
+			Locales:              getEnvAsList("ESTIMATE_LOCALES", estimate.DefaultLocales),
+			MinEstimate:          getEnv("MIN_ESTIMATE", ""),
+			MaxEstimate:          getEnv("MAX_ESTIMATE", "6 months"),
+			ConflictRule:         estimate.ConflictRule(getEnv("ESTIMATE_CONFLICT_RULE", string(estimate.RuleLast))),
+			Detectors:            getEnvAsList("ESTIMATE_DETECTORS", estimate.DefaultDetectors),
+			MinConfidence:        getEnvAsFloat("ESTIMATE_MIN_CONFIDENCE", estimate.DefaultMinConfidence),
+			DeferralTokens:       getEnvAsList("ESTIMATE_DEFERRAL_TOKENS", estimate.DefaultDeferralTokens),
+			DeferralLabel:        getEnv("DEFERRAL_LABEL", "needs-estimate"),
+			DeferralFollowUpDays: getEnvAsInt("DEFERRAL_FOLLOW_UP_DAYS", 7),
+			ResolvedReminders:    getEnv("RESOLVED_REMINDERS", ResolvedDelete),
+			ReopenedIssues:       getEnv("REOPENED_ISSUES", ReopenedCheck),
+			CommentEstimateRole:  getEnv("COMMENT_ESTIMATE_ROLE", RoleCollaborator),
+			CommandRoles:         getEnvAsMap("COMMAND_ROLES", DefaultCommandRoles),
+			NoEstimateLabel:      getEnv("NO_ESTIMATE_LABEL", "no-estimate-needed"),
+			ExemptLabels:         getEnvAsList("EXEMPT_LABELS", DefaultExemptLabels),
+			PullRequestCheck:     getEnvAsBool("PULL_REQUEST_CHECK", false),
+			TaskBreakdown:        getEnvAsBool("TASK_BREAKDOWN_COMMENT", false),
+			OnboardingScan:       getEnvAsBool("ONBOARDING_SCAN", false),
+		},
+	}
+
+	repos, err := loadRepoConfigs(getEnv("REPO_CONFIG_PATH", ""), config.Defaults)
+	if err != nil {
+		return nil, err
 	}
+	config.Repos = repos
 
 	if err := config.validate(); err != nil {
 		return nil, err
@@ -35,6 +180,14 @@ func Load() (*Config, error) {
 	return config, nil
 }
 
+// ForRepo returns the settings for a repository given as "owner/repo"
+func (c *Config) ForRepo(fullName string) RepoConfig {
+	if repo, ok := c.Repos[strings.ToLower(fullName)]; ok {
+		return repo
+	}
+	return c.Defaults
+}
+
 func (c *Config) validate() error {
 	if c.AppID == 0 {
 		return fmt.Errorf("GITHUB_APP_ID is required")
@@ -42,10 +195,153 @@ func (c *Config) validate() error {
 	if c.WebhookSecret == "" {
 		return fmt.Errorf("WEBHOOK_SECRET is required")
 	}
+	if c.WorkdayLength <= 0 {
+		return fmt.Errorf("WORKDAY_LENGTH must be a positive duration")
+	}
+	if c.SprintDays <= 0 {
+		return fmt.Errorf("SPRINT_LENGTH_DAYS must be positive")
+	}
+	if err := c.Defaults.validate(); err != nil {
+		return err
+	}
+	if _, _, err := c.EstimateBounds(c.Defaults); err != nil {
+		return err
+	}
+	for name, repo := range c.Repos {
+		if err := repo.validate(); err != nil {
+			return fmt.Errorf("repository %s: %v", name, err)
+		}
+		if _, _, err := c.EstimateBounds(repo); err != nil {
+			return fmt.Errorf("repository %s: %v", name, err)
+		}
+		c.Repos[name] = repo
+	}
 
 	return nil
 }
 
+func (r *RepoConfig) validate() error {
+	scheme, err := estimate.ParseScheme(string(r.Scheme))
+	if err != nil {
+		return err
+	}
+	r.Scheme = scheme
+
+	rule, err := estimate.ParseConflictRule(string(r.ConflictRule))
+	if err != nil {
+		return err
+	}
+	r.ConflictRule = rule
+
+	if len(r.StoryPoints) == 0 {
+		return fmt.Errorf("story points must not be empty")
+	}
+	if len(r.TShirtSizes) == 0 {
+		return fmt.Errorf("T-shirt sizes must not be empty")
+	}
+	if err := estimate.ValidateLocales(r.Locales); err != nil {
+		return err
+	}
+	if err := estimate.ValidateDetectors(r.Detectors); err != nil {
+		return err
+	}
+	if r.MinConfidence < 0 || r.MinConfidence > 1 {
+		return fmt.Errorf("minimum confidence must be between 0 and 1")
+	}
+	switch r.ResolvedReminders {
+	case ResolvedDelete, ResolvedUpdate:
+	default:
+		return fmt.Errorf("resolved reminders must be %q or %q, got %q", ResolvedDelete, ResolvedUpdate, r.ResolvedReminders)
+	}
+	switch r.ReopenedIssues {
+	case ReopenedIgnore, ReopenedCheck, ReopenedReestimate:
+	default:
+		return fmt.Errorf("reopened issues must be %q, %q or %q, got %q",
+			ReopenedIgnore, ReopenedCheck, ReopenedReestimate, r.ReopenedIssues)
+	}
+	if err := validateRole(r.CommentEstimateRole); err != nil {
+		return fmt.Errorf("comment estimate role: %v", err)
+	}
+	for command, role := range r.CommandRoles {
+		if err := validateRole(role); err != nil {
+			return fmt.Errorf("command %s: %v", command, err)
+		}
+	}
+	if r.DeferralFollowUpDays < 0 {
+		return fmt.Errorf("deferral follow up days must not be negative")
+	}
+	for _, pattern := range slices.Concat(r.LabelPatterns, r.TitlePatterns) {
+		if strings.Count(pattern, "*") != 1 {
+			return fmt.Errorf("pattern %q must contain exactly one *", pattern)
+		}
+	}
+
+	return nil
+}
+
+func validateRole(role string) error {
+	switch role {
+	case RoleNone, RoleAuthor, RoleCollaborator, RoleMaintainer:
+		return nil
+	}
+	return fmt.Errorf("role must be %q, %q, %q or %q, got %q",
+		RoleNone, RoleAuthor, RoleCollaborator, RoleMaintainer, role)
+}
+
+// EstimateBounds converts the repository's MinEstimate and MaxEstimate to
+// working time, a missing bound is returned as 0
+func (c *Config) EstimateBounds(repo RepoConfig) (time.Duration, time.Duration, error) {
+	parser := estimate.NewParser(estimate.Options{
+		WorkdayLength: c.WorkdayLength,
+		SprintDays:    c.SprintDays,
+	})
+
+	bounds := make([]time.Duration, 2)
+	for i, bound := range []string{repo.MinEstimate, repo.MaxEstimate} {
+		if bound == "" {
+			continue
+		}
+		est, err := parser.ParseValue(bound)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid estimate bound %q: %v", bound, err)
+		}
+		bounds[i] = est.Duration
+	}
+
+	return bounds[0], bounds[1], nil
+}
+
+// loadRepoConfigs reads a JSON object of "owner/repo" to RepoConfig, each
+// entry is applied on top of defaults
+func loadRepoConfigs(path string, defaults RepoConfig) (map[string]RepoConfig, error) {
+	repos := map[string]RepoConfig{}
+	if path == "" {
+		return repos, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository config: %v", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse repository config: %v", err)
+	}
+
+	for name, entry := range raw {
+		repo := defaults
+		// entries add to the default command roles instead of replacing them
+		repo.CommandRoles = maps.Clone(defaults.CommandRoles)
+		if err := json.Unmarshal(entry, &repo); err != nil {
+			return nil, fmt.Errorf("failed to parse repository config for %s: %v", name, err)
+		}
+		repos[strings.ToLower(name)] = repo
+	}
+
+	return repos, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -53,11 +349,84 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func getEnvAsInt(key string) int64 {
+func getEnvAsInt(key string, defaultValue int64) int64 {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
 			return intValue
 		}
 	}
-	return 0
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList splits a comma separated value, dropping empty items
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvAsMap reads comma separated key=value pairs on top of defaultValue,
+// skipping items without a key or a value
+func getEnvAsMap(key string, defaultValue map[string]string) map[string]string {
+	values := maps.Clone(defaultValue)
+	for _, item := range getEnvAsList(key, nil) {
+		k, v, _ := strings.Cut(item, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if k == "" || v == "" {
+			continue
+		}
+		values[k] = v
+	}
+	return values
+}
+
+func getEnvAsFloatList(key string, defaultValue []float64) []float64 {
+	var values []float64
+	for _, item := range getEnvAsList(key, nil) {
+		value, err := strconv.ParseFloat(item, 64)
+		if err != nil {
+			return defaultValue
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
 }
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taman9333/issue-estimate-reminder/internal/estimate"
+)
+
+// setEnv sets the required settings and env on top for one test
+func setEnv(t *testing.T, env map[string]string) {
+	t.Setenv("GITHUB_APP_ID", "1")
+	t.Setenv("WEBHOOK_SECRET", "test_secret")
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+}
+
+func writeRepoConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "repos.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadRepoConfigs(t *testing.T) {
+	setEnv(t, nil)
+	cfg, err := Load()
+	require.NoError(t, err)
+	defaults := cfg.Defaults
+
+	tests := []struct {
+		name    string
+		content string
+		check   func(t *testing.T, repos map[string]RepoConfig)
+		err     string
+	}{
+		{
+			name:    "override on top of the defaults",
+			content: `{"Acme/API": {"scheme": "points", "deferral_follow_up_days": 3}}`,
+			check: func(t *testing.T, repos map[string]RepoConfig) {
+				repo, ok := repos["acme/api"]
+				require.True(t, ok, "names are lowercased")
+				assert.Equal(t, estimate.Scheme("points"), repo.Scheme)
+				assert.Equal(t, int64(3), repo.DeferralFollowUpDays)
+				assert.Equal(t, defaults.DeferralLabel, repo.DeferralLabel)
+				assert.Equal(t, defaults.CommandRoles, repo.CommandRoles)
+			},
+		},
+		{
+			name:    "command roles merged onto the defaults",
+			content: `{"acme/api": {"command_roles": {"estimate": "maintainer"}}}`,
+			check: func(t *testing.T, repos map[string]RepoConfig) {
+				assert.Equal(t, map[string]string{
+					"estimate":           RoleMaintainer,
+					"no-estimate-needed": RoleMaintainer,
+					"remind-me":          RoleAuthor,
+				}, repos["acme/api"].CommandRoles)
+				assert.Equal(t, RoleAuthor, defaults.CommandRoles["estimate"], "the defaults are left alone")
+			},
+		},
+		{
+			name:    "repositories don't share settings",
+			content: `{"acme/api": {"exempt_labels": ["triage"]}, "acme/web": {}}`,
+			check: func(t *testing.T, repos map[string]RepoConfig) {
+				assert.Equal(t, []string{"triage"}, repos["acme/api"].ExemptLabels)
+				assert.Equal(t, DefaultExemptLabels, repos["acme/web"].ExemptLabels)
+			},
+		},
+		{name: "malformed JSON", content: `{"acme/api": `, err: "failed to parse repository config"},
+		{name: "malformed entry", content: `{"acme/api": {"scheme": 3}}`, err: "failed to parse repository config for acme/api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, err := loadRepoConfigs(writeRepoConfig(t, tt.content), defaults)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, repos)
+		})
+	}
+
+	t.Run("no path", func(t *testing.T) {
+		repos, err := loadRepoConfigs("", defaults)
+		require.NoError(t, err)
+		assert.Empty(t, repos)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := loadRepoConfigs(filepath.Join(t.TempDir(), "missing.json"), defaults)
+		assert.ErrorContains(t, err, "failed to read repository config")
+	})
+}
+
+func TestLoad_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		repos string // repository config file content
+		err   string
+	}{
+		{name: "valid"},
+		{name: "missing app id", env: map[string]string{"GITHUB_APP_ID": "0"}, err: "GITHUB_APP_ID is required"},
+		{name: "unknown conflict rule", env: map[string]string{"ESTIMATE_CONFLICT_RULE": "first"}, err: `unknown conflict rule "first"`},
+		{name: "unknown comment role", env: map[string]string{"COMMENT_ESTIMATE_ROLE": "everyone"}, err: "comment estimate role: role must be"},
+		{name: "unknown command role", env: map[string]string{"COMMAND_ROLES": "estimate=owner"}, err: "command estimate: role must be"},
+		{name: "unknown resolved reminders", env: map[string]string{"RESOLVED_REMINDERS": "keep"}, err: "resolved reminders must be"},
+		{name: "unknown reopened issues", env: map[string]string{"REOPENED_ISSUES": "close"}, err: "reopened issues must be"},
+		{name: "unknown detector", env: map[string]string{"ESTIMATE_DETECTORS": "body,commits"}, err: `unknown estimate detector "commits"`},
+		{name: "negative follow up", env: map[string]string{"DEFERRAL_FOLLOW_UP_DAYS": "-1"}, err: "must not be negative"},
+		{name: "pattern without a wildcard", env: map[string]string{"ESTIMATE_LABEL_PATTERNS": "estimate"}, err: "must contain exactly one *"},
+		{
+			name:  "invalid role in a repository",
+			repos: `{"acme/api": {"command_roles": {"remind-me": "anyone"}}}`,
+			err:   "repository acme/api: command remind-me: role must be",
+		},
+		{
+			name:  "invalid rule in a repository",
+			repos: `{"acme/api": {"conflict_rule": "oldest"}}`,
+			err:   `repository acme/api: unknown conflict rule "oldest"`,
+		},
+		{
+			name:  "rule names are normalized",
+			repos: `{"acme/api": {"conflict_rule": "REVISED"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			if tt.repos != "" {
+				env["REPO_CONFIG_PATH"] = writeRepoConfig(t, tt.repos)
+			}
+			setEnv(t, env)
+
+			_, err := Load()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestGetEnvAsMap(t *testing.T) {
+	defaults := map[string]string{"estimate": RoleAuthor, "remind-me": RoleAuthor}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]string
+	}{
+		{name: "unset", value: "", expected: defaults},
+		{
+			name:  "overrides and additions",
+			value: " estimate = maintainer ,no-estimate-needed=collaborator",
+			expected: map[string]string{
+				"estimate":           RoleMaintainer,
+				"remind-me":          RoleAuthor,
+				"no-estimate-needed": RoleCollaborator,
+			},
+		},
+		{
+			name:     "malformed entries are skipped",
+			value:    "estimate,=maintainer,remind-me=,,no-estimate-needed=none",
+			expected: map[string]string{"estimate": RoleAuthor, "remind-me": RoleAuthor, "no-estimate-needed": RoleNone},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_MAP", tt.value)
+			assert.Equal(t, tt.expected, getEnvAsMap("TEST_MAP", defaults))
+			assert.Equal(t, map[string]string{"estimate": RoleAuthor, "remind-me": RoleAuthor}, defaults, "defaults are left alone")
+		})
+	}
+}
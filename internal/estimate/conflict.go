@@ -0,0 +1,82 @@
+package estimate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConflictRule decides which estimate counts when an issue has several
+// that disagree
+type ConflictRule string
+
+const (
+	// RuleLast uses the estimate written last
+	RuleLast ConflictRule = "last"
+	// RuleRevised uses the last estimate marked as "Revised estimate:"
+	RuleRevised ConflictRule = "revised"
+)
+
+// ParseConflictRule validates a rule name, an empty name means RuleLast
+func ParseConflictRule(name string) (ConflictRule, error) {
+	switch rule := ConflictRule(strings.ToLower(name)); rule {
+	case "":
+		return RuleLast, nil
+	case RuleLast, RuleRevised:
+		return rule, nil
+	}
+	return "", fmt.Errorf("unknown conflict rule %q", name)
+}
+
+// Resolution is the outcome of picking one estimate out of several
+type Resolution struct {
+	Estimate *Estimate   // the authoritative estimate
+	Conflict bool        // the estimates disagree
+	Explicit bool        // Estimate was picked by the rule rather than as a fallback
+	All      []*Estimate // every estimate that was considered
+}
+
+// Resolve picks the authoritative estimate out of ests, which must be in
+// the order they were written. With RuleRevised and no estimate marked as
+// revised the last one is used but Explicit is false, so callers can ask
+// which one is meant.
+func Resolve(ests []*Estimate, rule ConflictRule) Resolution {
+	if len(ests) == 0 {
+		return Resolution{}
+	}
+
+	res := Resolution{Estimate: ests[len(ests)-1], Explicit: true, All: ests}
+	for _, est := range ests[1:] {
+		if !est.Equivalent(ests[0]) {
+			res.Conflict = true
+			break
+		}
+	}
+
+	if rule == RuleRevised && res.Conflict {
+		res.Explicit = false
+		for i := len(ests) - 1; i >= 0; i-- {
+			if ests[i].Revised {
+				res.Estimate = ests[i]
+				res.Explicit = true
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// Ambiguous reports whether the estimates disagree without one marked as
+// revised settling which counts
+func (r Resolution) Ambiguous() bool {
+	return r.Conflict && !(r.Explicit && r.Estimate.Revised)
+}
+
+// Equivalent reports whether two estimates amount to the same value, so
+// "3 days" and "24h" agree with an 8 hour workday
+func (e *Estimate) Equivalent(other *Estimate) bool {
+	if e.Duration > 0 || other.Duration > 0 {
+		return e.LowDuration == other.LowDuration && e.HighDuration == other.HighDuration
+	}
+	return e.Unit == other.Unit && e.Low == other.Low && e.High == other.High
+}
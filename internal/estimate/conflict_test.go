@@ -0,0 +1,88 @@
+package estimate
+
+import (
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+
+	tests := []struct {
+		name     string
+		body     string
+		rule     ConflictRule
+		expected string
+		conflict bool
+		explicit bool
+		// ambiguous needs the issue author to say which estimate counts
+		ambiguous bool
+	}{
+		{
+			name:     "Single estimate",
+			body:     "Estimate: 3 days",
+			rule:     RuleLast,
+			expected: "Estimate: 3 days",
+			explicit: true,
+		},
+		{
+			name:     "Same value written twice",
+			body:     "Estimate: 3 days\nEstimate: 24h",
+			rule:     RuleLast,
+			expected: "Estimate: 24h",
+			explicit: true,
+		},
+		{
+			name:      "Last one wins",
+			body:      "Estimate: 3 days\nRevised estimate: 5 days\nEstimate: 4 days",
+			rule:      RuleLast,
+			expected:  "Estimate: 4 days",
+			conflict:  true,
+			explicit:  true,
+			ambiguous: true,
+		},
+		{
+			name:     "Revised estimate last",
+			body:     "Estimate: 3 days\nRevised estimate: 5 days",
+			rule:     RuleLast,
+			expected: "Revised estimate: 5 days",
+			conflict: true,
+			explicit: true,
+		},
+		{
+			name:     "Revised estimate wins",
+			body:     "Estimate: 3 days\nRevised estimate: 5 days\nEstimate: 4 days",
+			rule:     RuleRevised,
+			expected: "Revised estimate: 5 days",
+			conflict: true,
+			explicit: true,
+		},
+		{
+			name:      "No revised estimate to pick",
+			body:      "Estimate: 3 days\nEstimate: 5 days",
+			rule:      RuleRevised,
+			expected:  "Estimate: 5 days",
+			conflict:  true,
+			explicit:  false,
+			ambiguous: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Resolve(parser.ParseAll(tt.body), tt.rule)
+			if res.Estimate == nil {
+				t.Fatal("Resolve() picked no estimate")
+			}
+			if res.Estimate.Text != tt.expected {
+				t.Errorf("Resolve() = %q, expected %q", res.Estimate.Text, tt.expected)
+			}
+			if res.Conflict != tt.conflict || res.Explicit != tt.explicit {
+				t.Errorf("Resolve() conflict = %v, explicit = %v, expected %v, %v",
+					res.Conflict, res.Explicit, tt.conflict, tt.explicit)
+			}
+			if res.Ambiguous() != tt.ambiguous {
+				t.Errorf("Ambiguous() = %v, expected %v", res.Ambiguous(), tt.ambiguous)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package estimate
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultDeferralTokens put an estimate off until more is known
+var DefaultDeferralTokens = []string{"TBD", "TBC", "TBA", "unknown", "needs spike", "needs investigation"}
+
+// parseValue parses the estimate value at the start of s using the
+// parser's scheme, a deferral token is returned as an *InvalidError
+// wrapping ErrDeferred
+func (p *Parser) parseValue(s string) (*Estimate, int, error) {
+	if n, ok := p.deferral(s); ok {
+		return nil, n, &InvalidError{Reason: "it puts the estimate off", Err: ErrDeferred}
+	}
+	return p.spec.parse(p, s)
+}
+
+// deferral reports whether s starts with one of the parser's deferral
+// tokens as whole words, ignoring case, and where the token ends
+func (p *Parser) deferral(s string) (int, bool) {
+	start := len(s) - len(strings.TrimLeftFunc(s, unicode.IsSpace))
+	for _, token := range p.opts.DeferralTokens {
+		end := start + len(token)
+		if end > len(s) || !strings.EqualFold(s[start:end], token) {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(s[end:]); end < len(s) && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			continue
+		}
+		return end, true
+	}
+	return 0, false
+}
@@ -0,0 +1,50 @@
+package estimate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParser_Parse_Deferral(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+
+	tests := []struct {
+		name     string
+		text     string
+		deferred bool
+		expected string
+	}{
+		{name: "TBD", text: "Estimate: TBD", deferred: true, expected: "Estimate: TBD"},
+		{name: "case insensitive", text: "Estimate: tbd", deferred: true, expected: "Estimate: tbd"},
+		{name: "multiple words", text: "Estimate: needs spike, see #12", deferred: true, expected: "Estimate: needs spike"},
+		{name: "part of a word", text: "Estimate: TBDays"},
+		{name: "estimate wins", text: "Estimate: TBD\nRevised estimate: 3 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.text)
+			var invalidErr *InvalidError
+			if errors.Is(err, ErrDeferred) != tt.deferred {
+				t.Fatalf("Parse(%q) error = %v, deferred expected %v", tt.text, err, tt.deferred)
+			}
+			if tt.deferred && (!errors.As(err, &invalidErr) || invalidErr.Text != tt.expected) {
+				t.Errorf("Parse(%q) error = %v, expected text %q", tt.text, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParser_Deferral_Sources(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+
+	if _, err := parser.ParseLabels([]string{"estimate/TBD"}); !errors.Is(err, ErrDeferred) {
+		t.Errorf("ParseLabels() error = %v, expected ErrDeferred", err)
+	}
+	if _, err := parser.ParseForm("### Estimate\n\nunknown"); !errors.Is(err, ErrDeferred) {
+		t.Errorf("ParseForm() error = %v, expected ErrDeferred", err)
+	}
+	if _, err := NewParser(Options{}).Parse("Estimate: TBD"); errors.Is(err, ErrDeferred) {
+		t.Errorf("Parse() error = %v, expected deferral tokens to be disabled", err)
+	}
+}
@@ -0,0 +1,236 @@
+package estimate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// Issue is the part of an issue detectors look for estimates in
+type Issue struct {
+	Title  string
+	Body   string
+	Labels []string
+	// Comments are the bodies of the comments allowed to carry the
+	// estimate, oldest first
+	Comments []string
+}
+
+// Detector finds estimates in an issue using the rules of a Parser. It
+// returns every valid estimate it found, with none it returns the first
+// rejected one as an *InvalidError, or ErrNoEstimate.
+type Detector interface {
+	Detect(p *Parser, issue Issue) ([]*Estimate, error)
+}
+
+// DetectorFunc adapts a function to a Detector
+type DetectorFunc func(p *Parser, issue Issue) ([]*Estimate, error)
+
+// Detect calls f(p, issue)
+func (f DetectorFunc) Detect(p *Parser, issue Issue) ([]*Estimate, error) {
+	return f(p, issue)
+}
+
+// Names of the built-in detectors
+const (
+	DetectorBody        = "body"
+	DetectorFrontMatter = "front_matter"
+	DetectorForm        = "form"
+	DetectorTitle       = "title"
+	DetectorLabels      = "labels"
+	DetectorComments    = "comments"
+)
+
+// DefaultDetectors run every built-in detector, top of the issue first
+var DefaultDetectors = []string{DetectorFrontMatter, DetectorBody, DetectorForm, DetectorTitle, DetectorLabels, DetectorComments}
+
+var (
+	detectorsMu sync.RWMutex
+	detectors   = map[string]Detector{
+		DetectorBody: DetectorFunc(func(p *Parser, issue Issue) ([]*Estimate, error) {
+			ests, rejected := p.scan(issue.Body)
+			switch {
+			case len(ests) > 0:
+				return ests, nil
+			case rejected != nil:
+				return nil, rejected
+			}
+			return nil, ErrNoEstimate
+		}),
+		DetectorFrontMatter: DetectorFunc(func(p *Parser, issue Issue) ([]*Estimate, error) {
+			return single(p.ParseFrontMatter(issue.Body))
+		}),
+		DetectorForm: DetectorFunc(func(p *Parser, issue Issue) ([]*Estimate, error) {
+			return single(p.ParseForm(issue.Body))
+		}),
+		DetectorTitle: DetectorFunc(func(p *Parser, issue Issue) ([]*Estimate, error) {
+			return single(p.ParseTitle(issue.Title))
+		}),
+		DetectorLabels: DetectorFunc(func(p *Parser, issue Issue) ([]*Estimate, error) {
+			return single(p.ParseLabels(issue.Labels))
+		}),
+		DetectorComments: DetectorFunc(func(p *Parser, issue Issue) ([]*Estimate, error) {
+			// a rejected estimate in a discussion is not worth a reply, an
+			// accepted one was given after the issue and revises it
+			var ests []*Estimate
+			for _, comment := range issue.Comments {
+				for _, est := range p.ParseAll(comment) {
+					est.Source = SourceComment
+					est.Revised = true
+					ests = append(ests, est)
+				}
+			}
+			if len(ests) == 0 {
+				return nil, ErrNoEstimate
+			}
+			return ests, nil
+		}),
+		DetectorNatural: DetectorFunc(func(p *Parser, issue Issue) ([]*Estimate, error) {
+			if ests := p.ParseNatural(issue.Body); len(ests) > 0 {
+				return ests, nil
+			}
+			return nil, ErrNoEstimate
+		}),
+	}
+)
+
+func single(est *Estimate, err error) ([]*Estimate, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []*Estimate{est}, nil
+}
+
+// RegisterDetector makes a detector available under name so it can be
+// listed in a repository's detectors. It panics if d is nil or name is
+// already registered.
+func RegisterDetector(name string, d Detector) {
+	detectorsMu.Lock()
+	defer detectorsMu.Unlock()
+	if d == nil {
+		panic("estimate: RegisterDetector detector is nil")
+	}
+	if _, dup := detectors[name]; dup {
+		panic("estimate: RegisterDetector called twice for detector " + name)
+	}
+	detectors[name] = d
+}
+
+// Detectors returns the names of all registered detectors, sorted
+func Detectors() []string {
+	detectorsMu.RLock()
+	defer detectorsMu.RUnlock()
+	names := make([]string, 0, len(detectors))
+	for name := range detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateDetectors checks that every name is a registered detector
+func ValidateDetectors(names []string) error {
+	registered := Detectors()
+	for _, name := range names {
+		if !slices.Contains(registered, name) {
+			return fmt.Errorf("unknown estimate detector %q, expected one of %v", name, registered)
+		}
+	}
+	return nil
+}
+
+// Detect runs the named detectors in order and returns every valid estimate
+// they found. Estimates in the issue body come first in the order they are
+// written, whichever detector found them, followed by the others in
+// detector order. With none it returns the first rejected estimate as an
+// *InvalidError, or ErrNoEstimate. Unknown names are skipped, see
+// ValidateDetectors.
+func (p *Parser) Detect(issue Issue, names []string) ([]*Estimate, error) {
+	detectorsMu.RLock()
+	defer detectorsMu.RUnlock()
+
+	var ests []*Estimate
+	rejected := ErrNoEstimate
+	for _, name := range names {
+		d, ok := detectors[name]
+		if !ok {
+			continue
+		}
+
+		found, err := d.Detect(p, issue)
+		ests = append(ests, found...)
+		if err != nil && errors.Is(rejected, ErrNoEstimate) {
+			rejected = err
+		}
+	}
+
+	if len(ests) == 0 {
+		return nil, rejected
+	}
+	sort.SliceStable(ests, func(i, j int) bool {
+		if inBody(ests[i]) != inBody(ests[j]) {
+			return inBody(ests[i])
+		}
+		return inBody(ests[i]) && ests[i].Start < ests[j].Start
+	})
+	return ests, nil
+}
+
+// inBody reports whether est was written in the issue body, where its
+// Start orders it
+func inBody(est *Estimate) bool {
+	switch est.Source {
+	case SourceBody, SourceFrontMatter, SourceForm:
+		return true
+	}
+	return false
+}
+
+// NewPatternDetector returns a detector for estimates written in the issue
+// body in a team's own convention. pattern is a regular expression whose
+// first capture group holds the value, which is parsed like ParseValue,
+// e.g. `(?m)^ETA:\s*(.+)$`.
+func NewPatternDetector(pattern string) (Detector, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid detector pattern: %v", err)
+	}
+	if re.NumSubexp() < 1 {
+		return nil, fmt.Errorf("detector pattern %q has no capture group", pattern)
+	}
+
+	return DetectorFunc(func(p *Parser, issue Issue) ([]*Estimate, error) {
+		body := VisibleText(issue.Body)
+		var ests []*Estimate
+		rejected := ErrNoEstimate
+		for _, m := range re.FindAllStringSubmatchIndex(body, -1) {
+			if m[2] < 0 {
+				continue
+			}
+
+			est, err := p.ParseValue(body[m[2]:m[3]])
+			var invalidErr *InvalidError
+			switch {
+			case err == nil:
+				est.Start, est.End = m[0], m[1]
+				est.Text = body[m[0]:m[1]]
+				est.Line = lineAt(body, m[0])
+				est.Source = SourceBody
+				ests = append(ests, est)
+			case errors.As(err, &invalidErr) && errors.Is(rejected, ErrNoEstimate):
+				invalidErr.Start, invalidErr.End = m[0], m[1]
+				invalidErr.Text = body[m[0]:m[1]]
+				invalidErr.Line = lineAt(body, m[0])
+				rejected = invalidErr
+			}
+		}
+
+		if len(ests) == 0 {
+			return nil, rejected
+		}
+		return ests, nil
+	}), nil
+}
@@ -0,0 +1,146 @@
+package estimate
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParser_Detect(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+	issue := Issue{
+		Title:  "Fix login",
+		Body:   "Estimate: 3 days\n\n### Estimate\n\n2d",
+		Labels: []string{"bug", "estimate/1w"},
+	}
+
+	tests := []struct {
+		name     string
+		names    []string
+		expected []Source
+	}{
+		{name: "default order", names: DefaultDetectors, expected: []Source{SourceBody, SourceForm, SourceLabel}},
+		{name: "body before the rest", names: []string{DetectorLabels, DetectorBody}, expected: []Source{SourceBody, SourceLabel}},
+		{name: "body in written order", names: []string{DetectorForm, DetectorBody}, expected: []Source{SourceBody, SourceForm}},
+		{name: "unknown names are skipped", names: []string{"nope", DetectorForm}, expected: []Source{SourceForm}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ests, err := parser.Detect(issue, tt.names)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if len(ests) != len(tt.expected) {
+				t.Fatalf("Detect() found %d estimates, expected %d", len(ests), len(tt.expected))
+			}
+			for i, est := range ests {
+				if est.Source != tt.expected[i] {
+					t.Errorf("estimate %d source = %q, expected %q", i, est.Source, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParser_Detect_WrittenOrder(t *testing.T) {
+	opts := DefaultOptions()
+	opts.TitlePatterns = []string{"[*]"}
+	parser := NewParser(opts)
+	issue := Issue{
+		Title: "[3d] Fix login",
+		Body:  "---\nestimate: 1d\n---\n### Estimate\n\n2d\n\nRevised estimate: 4 days",
+	}
+
+	ests, err := parser.Detect(issue, []string{DetectorTitle, DetectorBody, DetectorForm, DetectorFrontMatter})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	var sources []Source
+	for _, est := range ests {
+		sources = append(sources, est.Source)
+	}
+	expected := []Source{SourceFrontMatter, SourceForm, SourceBody, SourceTitle}
+	if !slices.Equal(sources, expected) {
+		t.Errorf("Detect() sources = %v, expected %v", sources, expected)
+	}
+}
+
+func TestParser_Detect_Rejected(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+
+	_, err := parser.Detect(Issue{Body: "Estimate: 0 days"}, DefaultDetectors)
+	var invalidErr *InvalidError
+	if !errors.As(err, &invalidErr) {
+		t.Errorf("Detect() error = %v, expected an *InvalidError", err)
+	}
+
+	_, err = parser.Detect(Issue{Body: "No estimate here"}, DefaultDetectors)
+	if !errors.Is(err, ErrNoEstimate) {
+		t.Errorf("Detect() error = %v, expected ErrNoEstimate", err)
+	}
+}
+
+func TestParser_Detect_Comments(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+	issue := Issue{
+		Body:     "No estimate yet",
+		Comments: []string{"Estimate: 2 days", "Looks bigger. Estimate: 0 days", "Estimate: 1 week"},
+	}
+
+	ests, err := parser.Detect(issue, []string{DetectorBody, DetectorComments})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if len(ests) != 2 || ests[1].String() != "1 weeks" || ests[1].Source != SourceComment {
+		t.Errorf("Detect() = %v, expected the two valid comment estimates", ests)
+	}
+}
+
+func TestParser_Detect_CommentRevisesBody(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+	issue := Issue{Body: "Estimate: 3 days", Comments: []string{"Estimate: 2 days"}}
+
+	ests, err := parser.Detect(issue, DefaultDetectors)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	for _, rule := range []ConflictRule{RuleLast, RuleRevised} {
+		res := Resolve(ests, rule)
+		if res.Estimate.Text != "Estimate: 2 days" || res.Ambiguous() {
+			t.Errorf("Resolve(%s) = %q, ambiguous %v, expected the comment's estimate to settle it",
+				rule, res.Estimate.Text, res.Ambiguous())
+		}
+	}
+}
+
+func TestRegisterDetector(t *testing.T) {
+	d, err := NewPatternDetector(`(?m)^ETA:\s*(.+)$`)
+	if err != nil {
+		t.Fatalf("NewPatternDetector() error = %v", err)
+	}
+	RegisterDetector("test_eta", d)
+
+	if err := ValidateDetectors([]string{DetectorBody, "test_eta"}); err != nil {
+		t.Errorf("ValidateDetectors() error = %v", err)
+	}
+	if err := ValidateDetectors([]string{"missing"}); err == nil {
+		t.Error("ValidateDetectors() expected an error for an unknown detector")
+	}
+
+	ests, err := NewParser(DefaultOptions()).Detect(Issue{Body: "Fix it\nETA: 2d\n"}, []string{"test_eta"})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if len(ests) != 1 || ests[0].String() != "2 days" || ests[0].Line != 2 {
+		t.Errorf("Detect() = %v, expected 2 days on line 2", ests)
+	}
+}
+
+func TestNewPatternDetector_Invalid(t *testing.T) {
+	for _, pattern := range []string{`ETA: (`, `ETA: \d+d`} {
+		if _, err := NewPatternDetector(pattern); err == nil {
+			t.Errorf("NewPatternDetector(%q) expected an error", pattern)
+		}
+	}
+}
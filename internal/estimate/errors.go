@@ -0,0 +1,100 @@
+package estimate
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrNoEstimate is returned when the parsed text holds no estimate at all
+var ErrNoEstimate = errors.New("no estimate found")
+
+// ErrDeferred is wrapped by the *InvalidError returned for an estimate
+// explicitly put off, e.g. "Estimate: TBD"
+var ErrDeferred = errors.New("estimate deferred")
+
+// InvalidError is returned for text that looks like an estimate but was
+// rejected, e.g. "Estimate: 0 days" or the "Estimate: X days" placeholder
+type InvalidError struct {
+	Text   string // rejected text, e.g. "Estimate: 0 days"
+	Reason string // why it was rejected, e.g. "it must be more than zero"
+	Start  int    // byte offset of Text in the parsed input
+	End    int    // byte offset just past Text
+	Line   int    // 1-based line number of Text
+	Err    error  // underlying cause such as ErrDeferred, usually nil
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf("invalid estimate %q: %s", e.Text, e.Reason)
+}
+
+func (e *InvalidError) Unwrap() error {
+	return e.Err
+}
+
+func invalid(format string, args ...any) *InvalidError {
+	return &InvalidError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// placeholderWords stand in for a number in copied examples like "X days"
+var placeholderWords = []string{"x", "xx", "n"}
+
+// placeholder reports whether tokens start with a placeholder such as
+// "X", "?" or "<number>" instead of a value, and where it ends
+func placeholder(tokens []token) (int, bool) {
+	if len(tokens) == 0 {
+		return 0, false
+	}
+
+	first := tokens[0]
+	switch {
+	case first.kind == tokenWord && slices.Contains(placeholderWords, first.text):
+	case first.kind == tokenSymbol && first.text == "<":
+		for _, tok := range tokens {
+			if tok.kind == tokenSymbol && tok.text == ">" {
+				return tok.end, true
+			}
+		}
+		return 0, false
+	case first.kind == tokenSymbol && (first.text == "?" || first.text == "_"):
+	default:
+		return 0, false
+	}
+
+	if len(tokens) > 1 && tokens[1].kind == tokenWord {
+		return tokens[1].end, true
+	}
+	return first.end, true
+}
+
+// checkBounds rejects time estimates that are zero or outside the
+// configured minimum and maximum
+func (p *Parser) checkBounds(est *Estimate) error {
+	switch {
+	case est.LowDuration <= 0:
+		return invalid("it must be more than zero")
+	case p.opts.MinDuration > 0 && est.LowDuration < p.opts.MinDuration:
+		return invalid("it must be at least %s", p.describe(p.opts.MinDuration))
+	case p.opts.MaxDuration > 0 && est.HighDuration > p.opts.MaxDuration:
+		return invalid("it must be at most %s", p.describe(p.opts.MaxDuration))
+	}
+	return nil
+}
+
+// describe formats working time in days, or hours when under a day
+func (p *Parser) describe(d time.Duration) string {
+	if d < p.opts.WorkdayLength {
+		return formatCount(d.Hours(), "hour")
+	}
+	return formatCount(float64(d)/float64(p.opts.WorkdayLength), "day")
+}
+
+func formatCount(value float64, unit string) string {
+	text := strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0")
+	if text != "1" {
+		unit += "s"
+	}
+	return text + " " + unit
+}
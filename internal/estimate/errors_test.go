@@ -0,0 +1,98 @@
+package estimate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse_Rejections(t *testing.T) {
+	parser := NewParser(Options{MinDuration: time.Hour, MaxDuration: 120 * 8 * time.Hour})
+
+	tests := []struct {
+		name   string
+		body   string
+		text   string
+		reason string
+	}{
+		{
+			name:   "Zero",
+			body:   "Estimate: 0 days",
+			text:   "Estimate: 0 days",
+			reason: "it must be more than zero",
+		},
+		{
+			name:   "Below minimum",
+			body:   "Estimate: 0.5h",
+			text:   "Estimate: 0.5h",
+			reason: "it must be at least 1 hour",
+		},
+		{
+			name:   "Above maximum",
+			body:   "Bug\nEstimate: 400 days",
+			text:   "Estimate: 400 days",
+			reason: "it must be at most 120 days",
+		},
+		{
+			name:   "Range above maximum",
+			body:   "Estimate: 3-7 months",
+			text:   "Estimate: 3-7 months",
+			reason: "it must be at most 120 days",
+		},
+		{
+			name:   "Placeholder copied from the reminder",
+			body:   "Estimate: X days",
+			text:   "Estimate: X days",
+			reason: "it looks like the placeholder from the example, replace it with a number",
+		},
+		{
+			name:   "Angle bracket placeholder",
+			body:   "Estimate: <number> days",
+			text:   "Estimate: <number>",
+			reason: "it looks like the placeholder from the example, replace it with a number",
+		},
+		{
+			name:   "Unknown unit",
+			body:   "Estimate: 3 bananas",
+			text:   "Estimate: 3 bananas",
+			reason: `"bananas" is not a known unit`,
+		},
+		{
+			name:   "Missing unit",
+			body:   "Estimate: 3",
+			text:   "Estimate: 3",
+			reason: "it is missing a unit such as days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.body)
+
+			var invalidErr *InvalidError
+			if !errors.As(err, &invalidErr) {
+				t.Fatalf("Parse() error = %v, expected an *InvalidError", err)
+			}
+			if invalidErr.Text != tt.text || invalidErr.Reason != tt.reason {
+				t.Errorf("Parse() rejected %q because %q, expected %q because %q",
+					invalidErr.Text, invalidErr.Reason, tt.text, tt.reason)
+			}
+		})
+	}
+}
+
+func TestParse_ValidEstimateWinsOverRejection(t *testing.T) {
+	est, err := Parse("Estimate: X days\nEstimate: 2 days")
+	if err != nil {
+		t.Fatalf("Parse() error = %v, expected the valid estimate", err)
+	}
+	if est.Line != 2 {
+		t.Errorf("Parse() line = %d, expected 2", est.Line)
+	}
+}
+
+func TestParse_NoEstimate(t *testing.T) {
+	if _, err := Parse("Estimate: soon"); !errors.Is(err, ErrNoEstimate) {
+		t.Errorf("Parse() error = %v, expected ErrNoEstimate", err)
+	}
+}
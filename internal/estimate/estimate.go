@@ -0,0 +1,288 @@
+package estimate
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Unit is the unit an estimate value was written in
+type Unit string
+
+const (
+	UnitHours   Unit = "hours"
+	UnitDays    Unit = "days"
+	UnitWeeks   Unit = "weeks"
+	UnitMonths  Unit = "months"
+	UnitSprints Unit = "sprints"
+)
+
+const (
+	daysPerWeek  = 5
+	daysPerMonth = 20
+)
+
+// Options controls which estimates are accepted and how time estimates
+// are normalized to a duration
+type Options struct {
+	Scheme           Scheme        // estimation scheme, defaults to SchemeTime
+	WorkdayLength    time.Duration // working time in one estimated day
+	SprintDays       int64         // workdays in one sprint
+	Points           []float64     // allowed story points, defaults to DefaultPoints
+	Sizes            []string      // allowed T-shirt sizes, smallest first, defaults to DefaultSizes
+	LabelPatterns    []string      // label patterns with a "*" for the value, e.g. "estimate/*"
+	FormHeading      string        // issue form field holding the estimate, empty disables it
+	TitlePatterns    []string      // title patterns with a "*" for the value, e.g. "[*]"
+	FrontMatterField string        // front matter key holding the estimate, empty disables it
+	Locales          []string      // locales whose keywords and units are accepted, defaults to DefaultLocales
+	MinDuration      time.Duration // shortest accepted time estimate, 0 only rejects zero
+	MaxDuration      time.Duration // longest accepted time estimate, 0 means no limit
+	DeferralTokens   []string      // values putting the estimate off, e.g. "TBD", nil disables them
+}
+
+// DefaultOptions returns time estimates with an 8 hour workday and a two
+// week sprint
+func DefaultOptions() Options {
+	return Options{
+		Scheme:           SchemeTime,
+		WorkdayLength:    8 * time.Hour,
+		SprintDays:       10,
+		Points:           DefaultPoints,
+		Sizes:            DefaultSizes,
+		LabelPatterns:    DefaultLabelPatterns,
+		FormHeading:      DefaultFormHeading,
+		FrontMatterField: DefaultFrontMatterField,
+		Locales:          DefaultLocales,
+		DeferralTokens:   DefaultDeferralTokens,
+	}
+}
+
+// Source is the part of an issue an estimate was found in
+type Source string
+
+const (
+	SourceBody  Source = "body"
+	SourceForm  Source = "form field"
+	SourceLabel Source = "label"
+	SourceTitle Source = "title"
+	// SourceFrontMatter is a field of a leading YAML front matter block
+	SourceFrontMatter Source = "front matter"
+	SourceComment     Source = "comment"
+)
+
+// Estimate is a parsed estimate together with where it was found.
+// Single values have Low == High == Value; ranges ("2-4 days") and
+// tolerances ("3d ± 1d") report their bounds and use the midpoint as Value.
+type Estimate struct {
+	Value        float64       // expected value in Unit, e.g. 3 for "3 days"
+	Low          float64       // lower bound in Unit
+	High         float64       // upper bound in Unit
+	Unit         Unit          // unit the value was written in
+	Size         string        // T-shirt size as configured, only set for UnitSize
+	Approximate  bool          // written as "~5 days" or similar
+	Duration     time.Duration // Value normalized to working time
+	LowDuration  time.Duration // Low normalized to working time
+	HighDuration time.Duration // High normalized to working time
+	Revised      bool          // written as "Revised estimate: 5 days"
+	Natural      bool          // written as prose and found by ParseNatural
+	Confidence   float64       // 1 for explicit estimates, lower for natural language ones
+	Source       Source        // where the estimate was found
+	Text         string        // matched text, e.g. "Estimate: 3 days"
+	Start        int           // byte offset of Text in the parsed input
+	End          int           // byte offset just past Text
+	Line         int           // 1-based line number of Text
+}
+
+// Parser finds estimates in text and normalizes them using its Options
+type Parser struct {
+	opts   Options
+	spec   schemeSpec
+	locale Locale // enabled locales merged into one table
+
+	// matches a scheme keyword such as "Estimate:" (case insensitive),
+	// the value follows it
+	keywordPattern *regexp.Regexp
+	labelPatterns  []*regexp.Regexp
+	titlePatterns  []*regexp.Regexp
+
+	// lowercase keywords of locales writing decimals as "3,5"
+	commaKeywords map[string]bool
+}
+
+// NewParser creates a parser for opts, unset options take their value from
+// DefaultOptions. An unknown scheme falls back to SchemeTime so callers
+// should validate it with ParseScheme first.
+func NewParser(opts Options) *Parser {
+	spec, ok := schemes[opts.Scheme]
+	if !ok {
+		opts.Scheme = SchemeTime
+		spec = schemes[SchemeTime]
+	}
+	defaults := DefaultOptions()
+	if opts.WorkdayLength <= 0 {
+		opts.WorkdayLength = defaults.WorkdayLength
+	}
+	if opts.SprintDays <= 0 {
+		opts.SprintDays = defaults.SprintDays
+	}
+	if len(opts.Points) == 0 {
+		opts.Points = defaults.Points
+	}
+	if len(opts.Sizes) == 0 {
+		opts.Sizes = defaults.Sizes
+	}
+	if len(opts.Locales) == 0 {
+		opts.Locales = defaults.Locales
+	}
+	locale := mergeLocales(opts.Locales)
+
+	labelPatterns := make([]*regexp.Regexp, len(opts.LabelPatterns))
+	for i, pattern := range opts.LabelPatterns {
+		labelPatterns[i] = valuePatternFor(pattern, true)
+	}
+	titlePatterns := make([]*regexp.Regexp, len(opts.TitlePatterns))
+	for i, pattern := range opts.TitlePatterns {
+		titlePatterns[i] = valuePatternFor(pattern, false)
+	}
+
+	return &Parser{
+		opts:           opts,
+		spec:           spec,
+		locale:         locale,
+		keywordPattern: keywordPatternFor(spec, locale),
+		commaKeywords:  decimalCommaKeywords(opts.Locales),
+		labelPatterns:  labelPatterns,
+		titlePatterns:  titlePatterns,
+	}
+}
+
+var defaultParser = NewParser(DefaultOptions())
+
+// Parse returns the first estimate found in text using DefaultOptions
+func Parse(text string) (*Estimate, error) {
+	return defaultParser.Parse(text)
+}
+
+// Parse returns the first valid estimate found in the visible prose of a
+// markdown text, see VisibleText. If there is none it returns the first
+// rejected estimate as an *InvalidError, or ErrNoEstimate.
+func (p *Parser) Parse(text string) (*Estimate, error) {
+	ests, rejected := p.scan(text)
+	switch {
+	case len(ests) > 0:
+		return ests[0], nil
+	case rejected != nil:
+		return nil, rejected
+	}
+	return nil, ErrNoEstimate
+}
+
+// ParseAll returns every valid estimate in the visible prose of a markdown
+// text in the order they appear
+func (p *Parser) ParseAll(text string) []*Estimate {
+	ests, _ := p.scan(text)
+	return ests
+}
+
+// matches the word marking an estimate as the revised one, right before
+// the keyword, e.g. "Revised estimate: 5 days"
+var revisedPattern = regexp.MustCompile(`(?i)\b(?:revised|updated)[ \t]+$`)
+
+// scan finds all valid estimates in text along with the first rejected one
+func (p *Parser) scan(text string) ([]*Estimate, error) {
+	text = VisibleText(text)
+
+	var ests []*Estimate
+	var rejected error
+	for _, loc := range p.keywordPattern.FindAllStringIndex(text, -1) {
+		if p.midSentence(text, loc[0]) {
+			continue
+		}
+		est, n, err := p.forKeyword(text, loc[0]).parseValue(text[loc[1]:])
+		start, end := loc[0], loc[1]+n
+
+		var invalidErr *InvalidError
+		switch {
+		case err == nil:
+			lineStart := strings.LastIndexByte(text[:start], '\n') + 1
+			if m := revisedPattern.FindStringIndex(text[lineStart:start]); m != nil {
+				start = lineStart + m[0]
+				est.Revised = true
+			}
+			est.Start, est.End = start, end
+			est.Text = text[start:end]
+			est.Line = lineAt(text, start)
+			est.Source = SourceBody
+			ests = append(ests, est)
+		case errors.As(err, &invalidErr) && rejected == nil:
+			invalidErr.Start, invalidErr.End = start, end
+			invalidErr.Text = text[start:end]
+			invalidErr.Line = lineAt(text, start)
+			rejected = invalidErr
+		}
+	}
+
+	return ests, rejected
+}
+
+// forKeyword returns a parser reading the value after the keyword matched
+// at start the way the keyword's locale writes numbers
+func (p *Parser) forKeyword(text string, start int) *Parser {
+	keyword := strings.ToLower(text[start : start+strings.IndexByte(text[start:], ':')])
+	if p.locale.DecimalComma || !p.commaKeywords[keyword] {
+		return p
+	}
+	localized := *p
+	localized.locale.DecimalComma = true
+	return &localized
+}
+
+// lineAt returns the 1-based line number of offset in text
+func lineAt(text string, offset int) int {
+	return strings.Count(text[:offset], "\n") + 1
+}
+
+// Duration normalizes value in unit to working time
+func (p *Parser) Duration(value float64, unit Unit) time.Duration {
+	var days float64
+	switch unit {
+	case UnitHours:
+		return time.Duration(value * float64(time.Hour))
+	case UnitDays:
+		days = value
+	case UnitWeeks:
+		days = value * daysPerWeek
+	case UnitMonths:
+		days = value * daysPerMonth
+	case UnitSprints:
+		days = value * float64(p.opts.SprintDays)
+	}
+	return time.Duration(days * float64(p.opts.WorkdayLength))
+}
+
+// IsRange reports whether the estimate has distinct lower and upper bounds
+func (e *Estimate) IsRange() bool {
+	return e.Low != e.High
+}
+
+// String formats the estimate value and unit, e.g. "3 days" or "~2-4 days"
+func (e *Estimate) String() string {
+	if e.Unit == UnitSize {
+		return "size " + e.Size
+	}
+
+	value := formatNumber(e.Value)
+	if e.IsRange() {
+		value = formatNumber(e.Low) + "-" + formatNumber(e.High)
+	}
+	if e.Approximate {
+		value = "~" + value
+	}
+	return value + " " + string(e.Unit)
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
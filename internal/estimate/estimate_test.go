@@ -0,0 +1,226 @@
+package estimate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		found    bool
+		value    float64
+		duration time.Duration
+		text     string
+		line     int
+	}{
+		{
+			name:  "No estimate",
+			body:  "This is a bug that needs fixing",
+			found: false,
+		},
+		{
+			name:     "Estimate on second line",
+			body:     "Bug in login system\nEstimate: 3 days",
+			found:    true,
+			value:    3,
+			duration: 24 * time.Hour,
+			text:     "Estimate: 3 days",
+			line:     2,
+		},
+		{
+			name:     "Fractional single day",
+			body:     "estimate: 0.5 day",
+			found:    true,
+			value:    0.5,
+			duration: 4 * time.Hour,
+			text:     "estimate: 0.5 day",
+			line:     1,
+		},
+		{
+			name:     "Short hours",
+			body:     "Estimate: 6h",
+			found:    true,
+			value:    6,
+			duration: 6 * time.Hour,
+			text:     "Estimate: 6h",
+			line:     1,
+		},
+		{
+			name:     "Weeks",
+			body:     "Estimate: 2 weeks",
+			found:    true,
+			value:    2,
+			duration: 80 * time.Hour,
+			text:     "Estimate: 2 weeks",
+			line:     1,
+		},
+		{
+			name:     "Sprint",
+			body:     "Estimate: 1 sprint",
+			found:    true,
+			value:    1,
+			duration: 80 * time.Hour,
+			text:     "Estimate: 1 sprint",
+			line:     1,
+		},
+		{
+			name:  "Unknown unit",
+			body:  "Estimate: 3 bananas",
+			found: false,
+		},
+		{
+			name:  "Missing colon",
+			body:  "Estimate 3 days",
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := Parse(tt.body)
+			ok := err == nil
+			if ok != tt.found {
+				t.Fatalf("Parse() found = %v, expected %v for body: %s", ok, tt.found, tt.body)
+			}
+			if !ok {
+				return
+			}
+			if est.Value != tt.value || est.Duration != tt.duration {
+				t.Errorf("Parse() = %v (%v), expected %v days (%v)", est, est.Duration, tt.value, tt.duration)
+			}
+			if est.Text != tt.text || tt.body[est.Start:est.End] != tt.text {
+				t.Errorf("Parse() text = %q, expected %q", est.Text, tt.text)
+			}
+			if est.Line != tt.line {
+				t.Errorf("Parse() line = %d, expected %d", est.Line, tt.line)
+			}
+		})
+	}
+}
+
+func TestParser_Duration(t *testing.T) {
+	parser := NewParser(Options{WorkdayLength: 6 * time.Hour, SprintDays: 15})
+
+	tests := []struct {
+		value    float64
+		unit     Unit
+		expected time.Duration
+	}{
+		{value: 90, unit: UnitHours, expected: 90 * time.Hour},
+		{value: 1.5, unit: UnitDays, expected: 9 * time.Hour},
+		{value: 1, unit: UnitWeeks, expected: 30 * time.Hour},
+		{value: 1, unit: UnitMonths, expected: 120 * time.Hour},
+		{value: 2, unit: UnitSprints, expected: 180 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.unit), func(t *testing.T) {
+			if result := parser.Duration(tt.value, tt.unit); result != tt.expected {
+				t.Errorf("Duration(%v, %s) = %v, expected %v", tt.value, tt.unit, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParse_RangesAndTolerances(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		low         float64
+		value       float64
+		high        float64
+		unit        Unit
+		approximate bool
+		text        string
+	}{
+		{
+			name:  "Range with shared unit",
+			body:  "Estimate: 2-4 days",
+			low:   2,
+			value: 3,
+			high:  4,
+			unit:  UnitDays,
+			text:  "Estimate: 2-4 days",
+		},
+		{
+			name:  "Range with to",
+			body:  "Estimate: 1 to 2 weeks",
+			low:   1,
+			value: 1.5,
+			high:  2,
+			unit:  UnitWeeks,
+			text:  "Estimate: 1 to 2 weeks",
+		},
+		{
+			name:  "Range with mixed units",
+			body:  "Estimate: 4h – 1d",
+			low:   0.5,
+			value: 0.75,
+			high:  1,
+			unit:  UnitDays,
+			text:  "Estimate: 4h – 1d",
+		},
+		{
+			name:  "Tolerance",
+			body:  "Estimate: 3d ± 1d",
+			low:   2,
+			value: 3,
+			high:  4,
+			unit:  UnitDays,
+			text:  "Estimate: 3d ± 1d",
+		},
+		{
+			name:  "Tolerance written as +/-",
+			body:  "Estimate: 3 +/- 1 days",
+			low:   2,
+			value: 3,
+			high:  4,
+			unit:  UnitDays,
+			text:  "Estimate: 3 +/- 1 days",
+		},
+		{
+			name:        "Approximate",
+			body:        "Estimate: ~5 days",
+			low:         5,
+			value:       5,
+			high:        5,
+			unit:        UnitDays,
+			approximate: true,
+			text:        "Estimate: ~5 days",
+		},
+		{
+			name:  "Dash without a second number",
+			body:  "Estimate: 3 days - blocked on review",
+			low:   3,
+			value: 3,
+			high:  3,
+			unit:  UnitDays,
+			text:  "Estimate: 3 days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := Parse(tt.body)
+			ok := err == nil
+			if !ok {
+				t.Fatalf("Parse() found no estimate in: %s", tt.body)
+			}
+			if est.Low != tt.low || est.Value != tt.value || est.High != tt.high || est.Unit != tt.unit {
+				t.Errorf("Parse() = %v..%v..%v %s, expected %v..%v..%v %s",
+					est.Low, est.Value, est.High, est.Unit, tt.low, tt.value, tt.high, tt.unit)
+			}
+			if est.Approximate != tt.approximate {
+				t.Errorf("Parse() approximate = %v, expected %v", est.Approximate, tt.approximate)
+			}
+			if est.Text != tt.text {
+				t.Errorf("Parse() text = %q, expected %q", est.Text, tt.text)
+			}
+			if est.LowDuration > est.Duration || est.Duration > est.HighDuration {
+				t.Errorf("Parse() durations out of order: %v, %v, %v", est.LowDuration, est.Duration, est.HighDuration)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package estimate
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// DefaultFormHeading is the issue form field label holding the estimate
+const DefaultFormHeading = "Estimate"
+
+// noResponse is what GitHub renders for an issue form field left empty
+const noResponse = "_No response_"
+
+// matches a markdown ATX heading, issue forms render every field label as
+// "### Label" followed by its value
+var headingPattern = regexp.MustCompile(`(?m)^[ \t]{0,3}#{1,6}[ \t]+(.*?)[ \t#]*$`)
+
+// FormSection returns the trimmed content under the first heading whose
+// text equals heading (case insensitive), up to the next heading, along
+// with the byte offset of the content in body. An "_No response_" field
+// is reported as found but empty.
+func FormSection(body, heading string) (string, int, bool) {
+	headings := headingPattern.FindAllStringSubmatchIndex(body, -1)
+	for i, loc := range headings {
+		if !strings.EqualFold(strings.TrimSpace(body[loc[2]:loc[3]]), heading) {
+			continue
+		}
+
+		end := len(body)
+		if i+1 < len(headings) {
+			end = headings[i+1][0]
+		}
+		section := body[loc[1]:end]
+
+		content := strings.TrimSpace(section)
+		if content == noResponse {
+			return "", loc[1], true
+		}
+		return content, loc[1] + strings.Index(section, content), true
+	}
+
+	return "", 0, false
+}
+
+// ParseForm returns the estimate written in the issue form section titled
+// with the parser's form heading, e.g. "### Estimate\n\n3 days". Like Parse
+// it only looks at visible prose and reports a rejected value as an
+// *InvalidError.
+func (p *Parser) ParseForm(body string) (*Estimate, error) {
+	if p.opts.FormHeading == "" {
+		return nil, ErrNoEstimate
+	}
+
+	body = VisibleText(body)
+	content, start, ok := FormSection(body, p.opts.FormHeading)
+	if !ok || content == "" {
+		return nil, ErrNoEstimate
+	}
+
+	est, n, err := p.parseValue(content)
+	if err != nil {
+		var invalidErr *InvalidError
+		if errors.As(err, &invalidErr) {
+			invalidErr.Start, invalidErr.End = start, start+n
+			invalidErr.Text = body[start : start+n]
+			invalidErr.Line = lineAt(body, start)
+		}
+		return nil, err
+	}
+
+	est.Start = start
+	est.End = start + n
+	est.Text = body[est.Start:est.End]
+	est.Line = lineAt(body, est.Start)
+	est.Source = SourceForm
+	return est, nil
+}
@@ -0,0 +1,91 @@
+package estimate
+
+import (
+	"testing"
+)
+
+func TestFormSection(t *testing.T) {
+	body := "### Description\n\nLogin fails\n\n### Estimate\n\n_No response_\n\n### Notes\n\nnone"
+
+	content, _, ok := FormSection(body, "estimate")
+	if !ok {
+		t.Fatal("FormSection() did not find the estimate section")
+	}
+	if content != "" {
+		t.Errorf("FormSection() = %q, expected empty content for _No response_", content)
+	}
+
+	content, start, ok := FormSection(body, "Description")
+	if !ok || content != "Login fails" || body[start:start+len(content)] != content {
+		t.Errorf("FormSection() = %q at %d, expected %q", content, start, "Login fails")
+	}
+
+	if _, _, ok := FormSection(body, "Priority"); ok {
+		t.Error("FormSection() found a section that does not exist")
+	}
+}
+
+func TestParser_ParseForm(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		body     string
+		found    bool
+		expected string
+		line     int
+	}{
+		{
+			name:     "Estimate field",
+			opts:     DefaultOptions(),
+			body:     "### Description\n\nLogin fails\n\n### Estimate\n\n3 days\n\n### Notes\n\nnone",
+			found:    true,
+			expected: "3 days",
+			line:     7,
+		},
+		{
+			name:  "No response",
+			opts:  DefaultOptions(),
+			body:  "### Estimate\n\n_No response_",
+			found: false,
+		},
+		{
+			name:  "Field is not an estimate",
+			opts:  DefaultOptions(),
+			body:  "### Estimate\n\nnot sure yet",
+			found: false,
+		},
+		{
+			name:     "Custom heading and scheme",
+			opts:     Options{Scheme: SchemeTShirt, FormHeading: "T-shirt size"},
+			body:     "### T-shirt size\n\nL",
+			found:    true,
+			expected: "size L",
+			line:     3,
+		},
+		{
+			name:  "Disabled",
+			opts:  Options{},
+			body:  "### Estimate\n\n3 days",
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := NewParser(tt.opts).ParseForm(tt.body)
+			ok := err == nil
+			if ok != tt.found {
+				t.Fatalf("ParseForm() found = %v, expected %v for body: %s", ok, tt.found, tt.body)
+			}
+			if !ok {
+				return
+			}
+			if est.String() != tt.expected || est.Line != tt.line {
+				t.Errorf("ParseForm() = %s on line %d, expected %s on line %d", est, est.Line, tt.expected, tt.line)
+			}
+			if tt.body[est.Start:est.End] != est.Text {
+				t.Errorf("ParseForm() text %q does not match its offsets", est.Text)
+			}
+		})
+	}
+}
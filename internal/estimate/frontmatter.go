@@ -0,0 +1,131 @@
+package estimate
+
+import (
+	"errors"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFrontMatterField is the front matter key holding the estimate
+const DefaultFrontMatterField = "estimate"
+
+// FrontMatter returns the YAML of a front matter block at the very start
+// of body, delimited by "---" lines, along with the byte offsets of the
+// YAML and of the whole block
+func FrontMatter(body string) (yamlText string, yamlStart, blockEnd int, ok bool) {
+	first, _, found := strings.Cut(body, "\n")
+	if !found || strings.TrimRight(first, " \t\r") != "---" {
+		return "", 0, 0, false
+	}
+
+	yamlStart = len(first) + 1
+	for offset := yamlStart; offset < len(body); {
+		line, _, _ := strings.Cut(body[offset:], "\n")
+		if delimiter := strings.TrimRight(line, " \t\r"); delimiter == "---" || delimiter == "..." {
+			blockEnd = min(offset+len(line)+1, len(body))
+			return body[yamlStart:offset], yamlStart, blockEnd, true
+		}
+		offset += len(line) + 1
+	}
+
+	return "", 0, 0, false
+}
+
+// ParseFrontMatter returns the estimate in the front matter field named by
+// the parser's options, e.g. "estimate: 3d" in a leading "---" block. The
+// value is parsed with the same rules as a body estimate.
+func (p *Parser) ParseFrontMatter(body string) (*Estimate, error) {
+	if p.opts.FrontMatterField == "" {
+		return nil, ErrNoEstimate
+	}
+
+	yamlText, yamlStart, _, ok := FrontMatter(body)
+	if !ok {
+		return nil, ErrNoEstimate
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(yamlText), &doc); err != nil || len(doc.Content) == 0 {
+		return nil, ErrNoEstimate
+	}
+
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, ErrNoEstimate
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if !strings.EqualFold(key.Value, p.opts.FrontMatterField) || value.Kind != yaml.ScalarNode {
+			continue
+		}
+
+		est, err := p.ParseValue(value.Value)
+		if err != nil {
+			var invalidErr *InvalidError
+			if errors.As(err, &invalidErr) {
+				invalidErr.Text = key.Value + ": " + value.Value
+				invalidErr.Line = value.Line + 1
+			}
+			return nil, err
+		}
+
+		// the YAML starts on the second line of body
+		start := lineOffset(yamlText, value.Line) + value.Column - 1
+		est.Start = yamlStart + start
+		est.End = yamlStart + scalarEnd(yamlText, start, value)
+		est.Text = value.Value
+		est.Line = value.Line + 1
+		est.Source = SourceFrontMatter
+		return est, nil
+	}
+
+	return nil, ErrNoEstimate
+}
+
+// scalarEnd returns the offset in text just past the scalar value starting
+// at start. Quoted scalars end after their closing quote, which can be
+// further than their value is long when it has escapes.
+func scalarEnd(text string, start int, value *yaml.Node) int {
+	switch {
+	case value.Style&yaml.DoubleQuotedStyle != 0:
+		for i := start + 1; i < len(text); i++ {
+			switch text[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+		return len(text)
+	case value.Style&yaml.SingleQuotedStyle != 0:
+		for i := start + 1; i < len(text); i++ {
+			if text[i] != '\'' {
+				continue
+			}
+			// a quote is escaped by doubling it
+			if i+1 < len(text) && text[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+		return len(text)
+	}
+
+	return start + len(value.Value)
+}
+
+// lineOffset returns the byte offset of the 1-based line in text
+func lineOffset(text string, line int) int {
+	offset := 0
+	for ; line > 1; line-- {
+		next := strings.IndexByte(text[offset:], '\n')
+		if next < 0 {
+			return offset
+		}
+		offset += next + 1
+	}
+	return offset
+}
@@ -0,0 +1,103 @@
+package estimate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParser_ParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		found    bool
+		expected string
+		line     int
+		span     string // body[Start:End]
+	}{
+		{
+			name:     "Estimate field",
+			body:     "---\npriority: high\nestimate: 3d\ncomponent: auth\n---\nRotate the signing keys",
+			found:    true,
+			expected: "3 days",
+			line:     3,
+			span:     "3d",
+		},
+		{
+			name:     "Windows line endings and quoted value",
+			body:     "---\r\nEstimate: \"2-4 days\"\r\n---\r\nBody",
+			found:    true,
+			expected: "2-4 days",
+			line:     2,
+			span:     `"2-4 days"`,
+		},
+		{
+			name:     "Single quoted value",
+			body:     "---\nestimate: '3 days' # rough\n---\nBody",
+			found:    true,
+			expected: "3 days",
+			line:     2,
+			span:     "'3 days'",
+		},
+		{
+			name:     "Double quoted value with escapes",
+			body:     "---\nestimate: \"3\\x20days\"\n---\nBody",
+			found:    true,
+			expected: "3 days",
+			line:     2,
+			span:     `"3\x20days"`,
+		},
+		{
+			name:  "No estimate field",
+			body:  "---\npriority: high\n---\nBody",
+			found: false,
+		},
+		{
+			name:  "Front matter not at the start",
+			body:  "Intro\n---\nestimate: 3d\n---",
+			found: false,
+		},
+		{
+			name:  "Unterminated block",
+			body:  "---\nestimate: 3d\nBody",
+			found: false,
+		},
+	}
+
+	parser := NewParser(DefaultOptions())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := parser.ParseFrontMatter(tt.body)
+			if found := err == nil; found != tt.found {
+				t.Fatalf("ParseFrontMatter() found = %v, expected %v for body: %q", found, tt.found, tt.body)
+			}
+			if !tt.found {
+				return
+			}
+			if est.String() != tt.expected || est.Line != tt.line {
+				t.Errorf("ParseFrontMatter() = %s on line %d, expected %s on line %d", est, est.Line, tt.expected, tt.line)
+			}
+			if span := tt.body[est.Start:est.End]; span != tt.span {
+				t.Errorf("ParseFrontMatter() spans %q, expected %q", span, tt.span)
+			}
+		})
+	}
+}
+
+func TestParser_ParseFrontMatter_Rejected(t *testing.T) {
+	_, err := NewParser(DefaultOptions()).ParseFrontMatter("---\nestimate: 0 days\n---\n")
+
+	var invalidErr *InvalidError
+	if !errors.As(err, &invalidErr) {
+		t.Fatalf("ParseFrontMatter() error = %v, expected an *InvalidError", err)
+	}
+	if invalidErr.Text != "estimate: 0 days" {
+		t.Errorf("ParseFrontMatter() rejected %q, expected %q", invalidErr.Text, "estimate: 0 days")
+	}
+}
+
+func TestParse_IgnoresFrontMatter(t *testing.T) {
+	ests := NewParser(DefaultOptions()).ParseAll("---\nestimate: 3 days\n---\nEstimate: 2 days")
+	if len(ests) != 1 || ests[0].Line != 4 {
+		t.Errorf("ParseAll() = %v, expected only the estimate after the front matter", ests)
+	}
+}
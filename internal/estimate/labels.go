@@ -0,0 +1,98 @@
+package estimate
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// DefaultLabelPatterns match labels such as "estimate/3d" or "size/M"
+var DefaultLabelPatterns = []string{"estimate/*", "size/*"}
+
+// valuePatternFor turns a label or title pattern into a regexp, the single
+// "*" in the pattern captures the estimate value. Anchored patterns must
+// match the whole input.
+func valuePatternFor(pattern string, anchored bool) *regexp.Regexp {
+	prefix, suffix, _ := strings.Cut(pattern, "*")
+	expr := `(?i)` + regexp.QuoteMeta(prefix) + `\s*(.+?)\s*` + regexp.QuoteMeta(suffix)
+	if anchored {
+		expr = `^` + expr + `$`
+	}
+	return regexp.MustCompile(expr)
+}
+
+// ParseLabels returns the estimate carried by the first label matching one
+// of the parser's label patterns. The value is parsed with the same rules
+// as a body estimate and must make up the whole captured part of the label.
+// If no label holds a valid estimate the first rejected one is returned as
+// an *InvalidError, or ErrNoEstimate.
+func (p *Parser) ParseLabels(labels []string) (*Estimate, error) {
+	var rejected error
+	for _, label := range labels {
+		for _, pattern := range p.labelPatterns {
+			match := pattern.FindStringSubmatch(label)
+			if match == nil {
+				continue
+			}
+
+			est, err := p.ParseValue(match[1])
+			var invalidErr *InvalidError
+			switch {
+			case err == nil:
+				est.Text = label
+				est.Source = SourceLabel
+				return est, nil
+			case errors.As(err, &invalidErr) && rejected == nil:
+				invalidErr.Text = label
+				rejected = invalidErr
+			}
+		}
+	}
+
+	if rejected != nil {
+		return nil, rejected
+	}
+	return nil, ErrNoEstimate
+}
+
+// IsEstimateLabel reports whether label matches one of the parser's label
+// patterns, whatever its value
+func (p *Parser) IsEstimateLabel(label string) bool {
+	for _, pattern := range p.labelPatterns {
+		if pattern.MatchString(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// EstimateLabel returns the label carrying value using the parser's first
+// label pattern, e.g. "estimate/3d", and false without label patterns
+func (p *Parser) EstimateLabel(value string) (string, bool) {
+	if len(p.opts.LabelPatterns) == 0 {
+		return "", false
+	}
+	return strings.Replace(p.opts.LabelPatterns[0], "*", strings.TrimSpace(value), 1), true
+}
+
+// ParseValue parses a bare estimate value such as "3d" or "M" without a
+// keyword in front of it, the whole of s must be a valid value
+func (p *Parser) ParseValue(s string) (*Estimate, error) {
+	s = strings.TrimSpace(s)
+	est, n, err := p.parseValue(s)
+	if err == nil && strings.TrimSpace(s[n:]) != "" {
+		return nil, ErrNoEstimate
+	}
+	if err != nil {
+		var invalidErr *InvalidError
+		if errors.As(err, &invalidErr) {
+			invalidErr.Text, invalidErr.End, invalidErr.Line = s, len(s), 1
+		}
+		return nil, err
+	}
+
+	est.Text = s
+	est.End = n
+	est.Line = 1
+	return est, nil
+}
@@ -0,0 +1,103 @@
+package estimate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParser_ParseLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		labels   []string
+		found    bool
+		expected string
+	}{
+		{
+			name:   "No labels",
+			opts:   DefaultOptions(),
+			labels: nil,
+			found:  false,
+		},
+		{
+			name:     "Estimate label",
+			opts:     DefaultOptions(),
+			labels:   []string{"bug", "estimate/3d"},
+			found:    true,
+			expected: "3 days",
+		},
+		{
+			name:     "Range in label",
+			opts:     DefaultOptions(),
+			labels:   []string{"Estimate/2-4 days"},
+			found:    true,
+			expected: "2-4 days",
+		},
+		{
+			name:   "Label value that is not an estimate",
+			opts:   DefaultOptions(),
+			labels: []string{"estimate/needed"},
+			found:  false,
+		},
+		{
+			name:     "Size label for T-shirt scheme",
+			opts:     Options{Scheme: SchemeTShirt, LabelPatterns: DefaultLabelPatterns},
+			labels:   []string{"size/M"},
+			found:    true,
+			expected: "size M",
+		},
+		{
+			name:     "Custom pattern",
+			opts:     Options{Scheme: SchemePoints, LabelPatterns: []string{"* points"}},
+			labels:   []string{"5 points"},
+			found:    true,
+			expected: "5 points",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := NewParser(tt.opts).ParseLabels(tt.labels)
+			ok := err == nil
+			if ok != tt.found {
+				t.Fatalf("ParseLabels() found = %v, expected %v for labels: %v", ok, tt.found, tt.labels)
+			}
+			if ok && est.String() != tt.expected {
+				t.Errorf("ParseLabels() = %s, expected %s", est, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParser_ParseValue(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+
+	est, err := parser.ParseValue(" 1.5w ")
+	ok := err == nil
+	if !ok {
+		t.Fatal("ParseValue() found no estimate")
+	}
+	if est.Duration != 60*time.Hour {
+		t.Errorf("ParseValue() duration = %v, expected %v", est.Duration, 60*time.Hour)
+	}
+
+	if _, err := parser.ParseValue("3 days and counting"); err == nil {
+		t.Error("ParseValue() accepted a value with trailing text")
+	}
+}
+
+func TestParser_EstimateLabel(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+
+	label, ok := parser.EstimateLabel(" 3d ")
+	if !ok || label != "estimate/3d" {
+		t.Errorf("EstimateLabel() = %q, %v, expected estimate/3d", label, ok)
+	}
+	if !parser.IsEstimateLabel(label) || !parser.IsEstimateLabel("size/TBD") || parser.IsEstimateLabel("bug") {
+		t.Error("IsEstimateLabel() did not match the label patterns")
+	}
+
+	if _, ok := NewParser(Options{}).EstimateLabel("3d"); ok {
+		t.Error("EstimateLabel() expected no label without label patterns")
+	}
+}
@@ -0,0 +1,142 @@
+package estimate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locale holds the words used to write estimates in one language. All
+// words are lowercase.
+type Locale struct {
+	Keywords     []string        // introduce an estimate, before the colon
+	Units        map[string]Unit // unit words and abbreviations
+	PointWords   []string        // words accepted after a story point value
+	RangeWords   []string        // join the bounds of a range, e.g. "to"
+	ApproxWords  []string        // mark an approximate value, e.g. "approx"
+	DecimalComma bool            // numbers may be written as "3,5"
+}
+
+// DefaultLocales are the locales enabled when none are configured
+var DefaultLocales = []string{"en"}
+
+var locales = map[string]Locale{
+	"en": {
+		Keywords: []string{"estimate"},
+		Units: map[string]Unit{
+			"h": UnitHours, "hr": UnitHours, "hrs": UnitHours, "hour": UnitHours, "hours": UnitHours,
+			"d": UnitDays, "day": UnitDays, "days": UnitDays,
+			"w": UnitWeeks, "wk": UnitWeeks, "wks": UnitWeeks, "week": UnitWeeks, "weeks": UnitWeeks,
+			"mo": UnitMonths, "month": UnitMonths, "months": UnitMonths,
+			"sprint": UnitSprints, "sprints": UnitSprints,
+		},
+		PointWords:  []string{"point", "points", "pt", "pts", "sp"},
+		RangeWords:  []string{"to"},
+		ApproxWords: []string{"approx"},
+	},
+	"es": {
+		Keywords: []string{"estimación", "estimacion", "estimado"},
+		Units: map[string]Unit{
+			"h": UnitHours, "hora": UnitHours, "horas": UnitHours,
+			"d": UnitDays, "día": UnitDays, "días": UnitDays, "dia": UnitDays, "dias": UnitDays,
+			"semana": UnitWeeks, "semanas": UnitWeeks,
+			"mes": UnitMonths, "meses": UnitMonths,
+			"sprint": UnitSprints, "sprints": UnitSprints,
+		},
+		PointWords:   []string{"punto", "puntos", "pts"},
+		RangeWords:   []string{"a"},
+		ApproxWords:  []string{"aprox"},
+		DecimalComma: true,
+	},
+	"de": {
+		Keywords: []string{"schätzung", "schaetzung", "aufwand"},
+		Units: map[string]Unit{
+			"h": UnitHours, "std": UnitHours, "stunde": UnitHours, "stunden": UnitHours,
+			"t": UnitDays, "tag": UnitDays, "tage": UnitDays, "tagen": UnitDays,
+			"wo": UnitWeeks, "woche": UnitWeeks, "wochen": UnitWeeks,
+			"monat": UnitMonths, "monate": UnitMonths, "monaten": UnitMonths,
+			"sprint": UnitSprints, "sprints": UnitSprints,
+		},
+		PointWords:   []string{"punkt", "punkte", "pkt"},
+		RangeWords:   []string{"bis"},
+		ApproxWords:  []string{"ca", "etwa"},
+		DecimalComma: true,
+	},
+	"fr": {
+		Keywords: []string{"estimation"},
+		Units: map[string]Unit{
+			"h": UnitHours, "heure": UnitHours, "heures": UnitHours,
+			"j": UnitDays, "jour": UnitDays, "jours": UnitDays,
+			"sem": UnitWeeks, "semaine": UnitWeeks, "semaines": UnitWeeks,
+			"mois":   UnitMonths,
+			"sprint": UnitSprints, "sprints": UnitSprints,
+		},
+		PointWords:   []string{"point", "points", "pts"},
+		RangeWords:   []string{"à", "a"},
+		ApproxWords:  []string{"env", "environ"},
+		DecimalComma: true,
+	},
+	"pt": {
+		Keywords: []string{"estimativa"},
+		Units: map[string]Unit{
+			"h": UnitHours, "hora": UnitHours, "horas": UnitHours,
+			"d": UnitDays, "dia": UnitDays, "dias": UnitDays,
+			"semana": UnitWeeks, "semanas": UnitWeeks,
+			"mês": UnitMonths, "mes": UnitMonths, "meses": UnitMonths,
+			"sprint": UnitSprints, "sprints": UnitSprints,
+		},
+		PointWords:   []string{"ponto", "pontos", "pts"},
+		RangeWords:   []string{"a"},
+		ApproxWords:  []string{"aprox"},
+		DecimalComma: true,
+	},
+}
+
+// ValidateLocales checks that every locale name is known
+func ValidateLocales(names []string) error {
+	for _, name := range names {
+		if _, ok := locales[strings.ToLower(name)]; !ok {
+			return fmt.Errorf("unknown locale %q", name)
+		}
+	}
+	return nil
+}
+
+// mergeLocales combines the named locales into one table, unknown names
+// are skipped. Decimal commas are only on when every locale uses them, so
+// "1,000 hours" stays a thousand with English enabled.
+func mergeLocales(names []string) Locale {
+	merged := Locale{Units: map[string]Unit{}}
+	found := 0
+	for _, name := range names {
+		locale, ok := locales[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		found++
+		merged.Keywords = append(merged.Keywords, locale.Keywords...)
+		for word, unit := range locale.Units {
+			merged.Units[word] = unit
+		}
+		merged.PointWords = append(merged.PointWords, locale.PointWords...)
+		merged.RangeWords = append(merged.RangeWords, locale.RangeWords...)
+		merged.ApproxWords = append(merged.ApproxWords, locale.ApproxWords...)
+		merged.DecimalComma = (found == 1 || merged.DecimalComma) && locale.DecimalComma
+	}
+	return merged
+}
+
+// decimalCommaKeywords returns the keywords of the named locales that use
+// decimal commas, a value after one of them is read the locale's way
+func decimalCommaKeywords(names []string) map[string]bool {
+	keywords := map[string]bool{}
+	for _, name := range names {
+		locale := locales[strings.ToLower(name)]
+		if !locale.DecimalComma {
+			continue
+		}
+		for _, keyword := range locale.Keywords {
+			keywords[keyword] = true
+		}
+	}
+	return keywords
+}
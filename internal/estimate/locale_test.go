@@ -0,0 +1,91 @@
+package estimate
+
+import (
+	"testing"
+)
+
+func TestParse_Locales(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		body     string
+		found    bool
+		expected string
+	}{
+		{
+			name:     "Spanish",
+			opts:     Options{Locales: []string{"en", "es"}},
+			body:     "Estimación: 3 días",
+			found:    true,
+			expected: "3 days",
+		},
+		{
+			name:     "German range",
+			opts:     Options{Locales: []string{"de"}},
+			body:     "Schätzung: 2 bis 3 Tage",
+			found:    true,
+			expected: "2-3 days",
+		},
+		{
+			name:     "French decimal comma",
+			opts:     Options{Locales: []string{"fr"}},
+			body:     "Estimation: 3,5 jours",
+			found:    true,
+			expected: "3.5 days",
+		},
+		{
+			name:     "Portuguese story points",
+			opts:     Options{Scheme: SchemePoints, Locales: []string{"pt"}},
+			body:     "Estimativa: 5 pontos",
+			found:    true,
+			expected: "5 points",
+		},
+		{
+			name:  "Locale not enabled",
+			opts:  Options{Locales: []string{"en"}},
+			body:  "Schätzung: 2 Tage",
+			found: false,
+		},
+		{
+			name:     "Decimal comma after a keyword of a locale that uses it",
+			opts:     Options{Locales: []string{"en", "de"}},
+			body:     "Schätzung: 1,5 Tage",
+			found:    true,
+			expected: "1.5 days",
+		},
+		{
+			name:  "Thousands separator with English enabled",
+			opts:  Options{Locales: []string{"en", "de"}},
+			body:  "Estimate: 1,000 hours",
+			found: false,
+		},
+		{
+			name:  "Decimal comma needs a locale that uses it",
+			opts:  Options{Locales: []string{"en"}},
+			body:  "Estimate: 3,5 days",
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := NewParser(tt.opts).Parse(tt.body)
+			ok := err == nil
+			if ok != tt.found {
+				t.Fatalf("Parse() found = %v, expected %v for body: %s", ok, tt.found, tt.body)
+			}
+			if ok && est.String() != tt.expected {
+				t.Errorf("Parse() = %s, expected %s", est, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateLocales(t *testing.T) {
+	if err := ValidateLocales([]string{"en", "DE"}); err != nil {
+		t.Errorf("ValidateLocales() error = %v, expected nil", err)
+	}
+	if err := ValidateLocales([]string{"xx"}); err == nil {
+		t.Error("ValidateLocales() accepted an unknown locale")
+	}
+}
@@ -0,0 +1,152 @@
+package estimate
+
+import (
+	"strings"
+)
+
+// VisibleText blanks out the parts of a markdown body that are not shown
+// as prose: a leading YAML front matter block, fenced code blocks, inline
+// code, block quotes and HTML comments such as issue template
+// placeholders. Hidden bytes are replaced with spaces and newlines are
+// kept, so offsets and line numbers in the result still point at the same
+// place in body.
+func VisibleText(body string) string {
+	out := []byte(body)
+	mask := func(start, end int) {
+		for i := start; i < end; i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	var fence string // marker of the open code fence, e.g. "```"
+	inComment := false
+
+	lineStart := 0
+	if _, _, end, ok := FrontMatter(body); ok {
+		mask(0, end)
+		lineStart = end
+	}
+
+	for lineStart < len(body) {
+		lineEnd := strings.IndexByte(body[lineStart:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(body)
+		} else {
+			lineEnd += lineStart
+		}
+		line := body[lineStart:lineEnd]
+		next := lineEnd + 1
+
+		switch {
+		case fence != "":
+			mask(lineStart, lineEnd)
+			if isFenceClose(line, fence) {
+				fence = ""
+			}
+			lineStart = next
+			continue
+		case !inComment:
+			if marker := fenceOpen(line); marker != "" {
+				fence = marker
+				mask(lineStart, lineEnd)
+				lineStart = next
+				continue
+			}
+			if isBlockQuote(line) {
+				mask(lineStart, lineEnd)
+				lineStart = next
+				continue
+			}
+		}
+
+		for i := 0; i < len(line); {
+			if inComment {
+				end := strings.Index(line[i:], "-->")
+				if end < 0 {
+					mask(lineStart+i, lineEnd)
+					break
+				}
+				mask(lineStart+i, lineStart+i+end+3)
+				i += end + 3
+				inComment = false
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(line[i:], "<!--"):
+				// masked from here on the next pass through the loop
+				mask(lineStart+i, lineStart+i+4)
+				i += 4
+				inComment = true
+			case line[i] == '`':
+				ticks := countRun(line[i:], '`')
+				end := closingTicks(line[i+ticks:], ticks)
+				if end < 0 {
+					i += ticks
+					continue
+				}
+				end += i + ticks*2
+				mask(lineStart+i, lineStart+end)
+				i = end
+			default:
+				i++
+			}
+		}
+
+		lineStart = next
+	}
+
+	return string(out)
+}
+
+// fenceOpen returns the marker of a code fence opened on line, if any
+func fenceOpen(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return ""
+	}
+	if c := trimmed[0]; c == '`' || c == '~' {
+		if n := countRun(trimmed, c); n >= 3 {
+			return trimmed[:n]
+		}
+	}
+	return ""
+}
+
+func isFenceClose(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	n := countRun(trimmed, fence[0])
+	return n >= len(fence) && n == len(trimmed)
+}
+
+func isBlockQuote(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	return len(line)-len(trimmed) <= 3 && strings.HasPrefix(trimmed, ">")
+}
+
+func countRun(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+// closingTicks finds a run of exactly n backticks in s closing an inline
+// code span and returns its offset, or -1
+func closingTicks(s string, n int) int {
+	for i := 0; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		run := countRun(s[i:], '`')
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
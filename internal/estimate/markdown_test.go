@@ -0,0 +1,82 @@
+package estimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVisibleText(t *testing.T) {
+	body := "Intro `Estimate: 1 day`\n" +
+		"<!-- Estimate: X days -->\n" +
+		"```\n" +
+		"Estimate: 2 days\n" +
+		"```\n" +
+		"> Estimate: 3 days\n" +
+		"<!--\n" +
+		"Estimate: 4 days\n" +
+		"--> Estimate: 5 days"
+
+	visible := VisibleText(body)
+
+	if len(visible) != len(body) || strings.Count(visible, "\n") != strings.Count(body, "\n") {
+		t.Fatalf("VisibleText() changed offsets: %q", visible)
+	}
+	for _, hidden := range []string{"1 day", "X days", "2 days", "3 days", "4 days"} {
+		if strings.Contains(visible, hidden) {
+			t.Errorf("VisibleText() kept hidden text %q", hidden)
+		}
+	}
+	if !strings.Contains(visible, "Intro") || !strings.HasSuffix(visible, "Estimate: 5 days") {
+		t.Errorf("VisibleText() hid visible text: %q", visible)
+	}
+}
+
+func TestParse_IgnoresHiddenMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		found bool
+	}{
+		{
+			name:  "Template placeholder comment",
+			body:  "Bug report\n<!-- Estimate: 3 days -->",
+			found: false,
+		},
+		{
+			name:  "Fenced code block",
+			body:  "Config example:\n~~~yaml\nEstimate: 3 days\n~~~",
+			found: false,
+		},
+		{
+			name:  "Quoted reply",
+			body:  "> Estimate: 3 days\n\nI disagree with that",
+			found: false,
+		},
+		{
+			name:  "Inline code",
+			body:  "Use the format `Estimate: 3 days`",
+			found: false,
+		},
+		{
+			name:  "Estimate after a code block",
+			body:  "```\nsome code\n```\nEstimate: 3 days",
+			found: true,
+		},
+		{
+			name:  "Form field inside a comment",
+			body:  "<!--\n### Estimate\n\n3 days\n-->",
+			found: false,
+		},
+	}
+
+	parser := NewParser(DefaultOptions())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, bodyErr := parser.Parse(tt.body)
+			_, formErr := parser.ParseForm(tt.body)
+			if found := bodyErr == nil || formErr == nil; found != tt.found {
+				t.Errorf("found = %v, expected %v for body: %s", found, tt.found, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package estimate
+
+import (
+	"math"
+	"slices"
+	"strings"
+)
+
+// DetectorNatural finds time estimates written as English prose, such as
+// "should take about a week and a half". It is not one of the
+// DefaultDetectors and has to be listed explicitly.
+const DetectorNatural = "natural_language"
+
+// DefaultMinConfidence is the confidence a natural language estimate needs
+// to be accepted without asking for confirmation
+const DefaultMinConfidence = 0.7
+
+// phrases scoring below this are too likely to be something else, like
+// "3 days ago", and are not reported at all
+const minNaturalConfidence = 0.3
+
+var (
+	numberWords = map[string]float64{
+		"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+		"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11,
+		"twelve": 12, "fifteen": 15, "twenty": 20,
+	}
+	// vague amounts, also marking the estimate approximate
+	vagueWords = map[string]float64{"couple": 2, "few": 3, "several": 3}
+
+	hedgeWords = []string{"about", "around", "roughly", "approximately", "approx", "maybe", "probably", "likely"}
+	// words shortly before a phrase saying it is about effort
+	effortWords = []string{"take", "takes", "taking", "need", "needs", "require", "requires", "estimate", "estimated", "effort"}
+	// words after "of" saying the phrase is about effort, "2 days of work"
+	workWords = []string{"work", "effort", "dev", "development", "coding"}
+	// verbs after "to" saying the phrase is about effort, "2 days to fix"
+	taskVerbs = []string{"fix", "implement", "build", "finish", "complete", "do", "ship", "write"}
+	// words right before a phrase placing it on a timeline instead
+	timelineWords = []string{"since", "last", "past", "every", "per", "in", "within", "after", "before", "for"}
+)
+
+// naturalPhrase is an amount of time found in prose, spanning tokens
+// [start, end)
+type naturalPhrase struct {
+	low, high   float64
+	unit        Unit
+	approximate bool
+	vague       bool
+	start, end  int
+}
+
+// phraseParser walks the tokens of one line looking for a natural
+// language amount of time
+type phraseParser struct {
+	tokens []token
+	pos    int
+}
+
+func (pp *phraseParser) word(words ...string) bool {
+	if pp.pos >= len(pp.tokens) || pp.tokens[pp.pos].kind != tokenWord {
+		return false
+	}
+	if slices.Contains(words, pp.tokens[pp.pos].text) {
+		pp.pos++
+		return true
+	}
+	return false
+}
+
+func (pp *phraseParser) symbol(symbols ...string) bool {
+	if pp.pos >= len(pp.tokens) || pp.tokens[pp.pos].kind != tokenSymbol {
+		return false
+	}
+	if slices.Contains(symbols, pp.tokens[pp.pos].text) {
+		pp.pos++
+		return true
+	}
+	return false
+}
+
+// andAHalf accepts "and a half"
+func (pp *phraseParser) andAHalf() bool {
+	mark := pp.pos
+	if pp.word("and") && pp.word("a") && pp.word("half") {
+		return true
+	}
+	pp.pos = mark
+	return false
+}
+
+// amount accepts a number, a number word or a vague amount such as
+// "a couple of", reporting whether it was a digit
+func (pp *phraseParser) amount() (value float64, digits, vague, ok bool) {
+	if pp.pos >= len(pp.tokens) {
+		return 0, false, false, false
+	}
+	tok := pp.tokens[pp.pos]
+	if tok.kind == tokenNumber {
+		pp.pos++
+		return tok.num, true, false, true
+	}
+	if tok.kind != tokenWord {
+		return 0, false, false, false
+	}
+
+	mark := pp.pos
+	pp.word("a")
+	if pp.pos < len(pp.tokens) {
+		if value, ok := vagueWords[pp.tokens[pp.pos].text]; ok {
+			pp.pos++
+			pp.word("of")
+			return value, false, true, true
+		}
+	}
+	pp.pos = mark
+
+	if value, ok := numberWords[tok.text]; ok {
+		pp.pos++
+		return value, false, false, true
+	}
+	return 0, false, false, false
+}
+
+// unit accepts a unit word, abbreviations like "d" only count after digits
+func (pp *phraseParser) unit(digits bool) (Unit, bool) {
+	if pp.pos >= len(pp.tokens) || pp.tokens[pp.pos].kind != tokenWord {
+		return "", false
+	}
+	text := pp.tokens[pp.pos].text
+	unit, ok := locales["en"].Units[text]
+	if !ok || !digits && len(text) < 3 {
+		return "", false
+	}
+	pp.pos++
+	return unit, true
+}
+
+// phrase parses an amount of time starting at token i, e.g. "half a day",
+// "roughly two to three days", "a week and a half" or "a day or two"
+func (pp *phraseParser) phrase(i int) (naturalPhrase, bool) {
+	pp.pos = i
+	ph := naturalPhrase{start: i}
+	ph.approximate = pp.word(hedgeWords...) || pp.symbol("~")
+
+	if pp.word("half") {
+		if !pp.word("a", "an") {
+			return ph, false
+		}
+		unit, ok := pp.unit(false)
+		if !ok {
+			return ph, false
+		}
+		ph.low, ph.high, ph.unit, ph.end = 0.5, 0.5, unit, pp.pos
+		return ph, true
+	}
+
+	low, digits, vague, ok := pp.amount()
+	if !ok {
+		return ph, false
+	}
+	if pp.andAHalf() {
+		low += 0.5
+	}
+	high := low
+
+	mark := pp.pos
+	if pp.word("to", "or") || pp.symbol("-") {
+		if value, d, v, ok := pp.amount(); ok {
+			high, digits, vague = value, digits || d, vague || v
+		} else {
+			pp.pos = mark
+		}
+	}
+
+	unit, ok := pp.unit(digits)
+	if !ok {
+		return ph, false
+	}
+
+	// "a week and a half", "a day or two"
+	if low == high && pp.andAHalf() {
+		low, high = low+0.5, high+0.5
+	} else if low == high {
+		mark := pp.pos
+		if pp.word("or", "to") {
+			if value, _, _, ok := pp.amount(); ok && value > low {
+				high = value
+			} else {
+				pp.pos = mark
+			}
+		}
+	}
+
+	ph.low, ph.high, ph.unit, ph.vague, ph.end = low, high, unit, vague, pp.pos
+	ph.approximate = ph.approximate || vague
+	return ph, true
+}
+
+// confidence scores how likely a phrase is an effort estimate from the
+// words around it, between 0 and 1
+func (ph naturalPhrase) confidence(tokens []token) float64 {
+	c := 0.4
+	for _, tok := range tokens[max(0, ph.start-4):ph.start] {
+		if slices.Contains(effortWords, tok.text) {
+			c += 0.3
+			break
+		}
+	}
+	if ph.approximate && !ph.vague {
+		c += 0.1
+	}
+	if ph.end+1 < len(tokens) {
+		next, after := tokens[ph.end].text, tokens[ph.end+1].text
+		if next == "of" && slices.Contains(workWords, after) || next == "to" && slices.Contains(taskVerbs, after) {
+			c += 0.2
+		}
+	}
+	if ph.vague {
+		c -= 0.1
+	}
+	if ph.start > 0 && slices.Contains(timelineWords, tokens[ph.start-1].text) {
+		c -= 0.3
+	}
+	if ph.end < len(tokens) && tokens[ph.end].text == "ago" {
+		c -= 0.5
+	}
+	return math.Round(min(max(c, 0), 1)*100) / 100
+}
+
+// ParseNatural returns the time estimates written as English prose in the
+// visible text of body, such as "should take about a week and a half" or
+// "roughly two to three days of work". Each is marked Natural and has a
+// Confidence below 1 telling how sure the match is, phrases after an
+// estimate keyword are left to Parse. Other schemes have no natural
+// language form.
+func (p *Parser) ParseNatural(body string) []*Estimate {
+	if p.opts.Scheme != SchemeTime {
+		return nil
+	}
+	body = VisibleText(body)
+
+	keywordEnds := map[int]bool{}
+	for _, loc := range p.keywordPattern.FindAllStringIndex(body, -1) {
+		keywordEnds[loc[1]] = true
+	}
+
+	var ests []*Estimate
+	lineStart := 0
+	for i, line := range strings.Split(body, "\n") {
+		tokens := p.lex(line)
+		pp := &phraseParser{tokens: tokens}
+		for j := 0; j < len(tokens); j++ {
+			ph, ok := pp.phrase(j)
+			if !ok {
+				continue
+			}
+			start, end := tokens[ph.start].start, tokens[ph.end-1].end
+			confidence := ph.confidence(tokens)
+			if ph.low <= 0 || confidence < minNaturalConfidence || keywordEnds[lineStart+start] {
+				j = ph.end - 1
+				continue
+			}
+
+			est := &Estimate{
+				Value:       (ph.low + ph.high) / 2,
+				Low:         ph.low,
+				High:        ph.high,
+				Unit:        ph.unit,
+				Approximate: ph.approximate,
+				Natural:     true,
+				Confidence:  confidence,
+				Source:      SourceBody,
+				Text:        line[start:end],
+				Start:       lineStart + start,
+				End:         lineStart + end,
+				Line:        i + 1,
+			}
+			est.Duration = p.Duration(est.Value, est.Unit)
+			est.LowDuration = p.Duration(est.Low, est.Unit)
+			est.HighDuration = p.Duration(est.High, est.Unit)
+			if p.checkBounds(est) == nil {
+				ests = append(ests, est)
+			}
+			j = ph.end - 1
+		}
+		lineStart += len(line) + 1
+	}
+	return ests
+}
@@ -0,0 +1,56 @@
+package estimate
+
+import "testing"
+
+func TestParser_ParseNatural(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+
+	tests := []struct {
+		name       string
+		text       string
+		expected   string
+		confidence float64
+	}{
+		{name: "a week and a half", text: "This should take about a week and a half.", expected: "~1.5 weeks", confidence: 0.8},
+		{name: "number word range", text: "Roughly two to three days of work", expected: "~2-3 days", confidence: 0.7},
+		{name: "half a day", text: "Needs half a day", expected: "0.5 days", confidence: 0.7},
+		{name: "a couple of", text: "It will take a couple of hours", expected: "~2 hours", confidence: 0.6},
+		{name: "one or the other", text: "A day or two to fix", expected: "1-2 days", confidence: 0.6},
+		{name: "digits", text: "Will take 3h", expected: "3 hours", confidence: 0.7},
+		{name: "no context", text: "Probably one sprint", expected: "~1 sprints", confidence: 0.5},
+		{name: "timeline", text: "Broken for 2 weeks, takes 4 days", expected: "4 days", confidence: 0.7},
+		{name: "ago", text: "This started 3 days ago"},
+		{name: "after keyword", text: "Estimate: 3 days"},
+		{name: "code", text: "`takes 2 days`"},
+		{name: "abbreviation after number word", text: "takes two d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ests := parser.ParseNatural(tt.text)
+			if tt.expected == "" {
+				if len(ests) != 0 {
+					t.Errorf("ParseNatural(%q) = %v, expected none", tt.text, ests[0])
+				}
+				return
+			}
+			if len(ests) != 1 {
+				t.Fatalf("ParseNatural(%q) found %d estimates, expected 1", tt.text, len(ests))
+			}
+			if !ests[0].Natural {
+				t.Errorf("ParseNatural(%q) is not marked Natural", tt.text)
+			}
+			if ests[0].String() != tt.expected || ests[0].Confidence != tt.confidence {
+				t.Errorf("ParseNatural(%q) = %s with confidence %v, expected %s with %v",
+					tt.text, ests[0], ests[0].Confidence, tt.expected, tt.confidence)
+			}
+		})
+	}
+}
+
+func TestParser_ParseNatural_OtherSchemes(t *testing.T) {
+	parser := NewParser(Options{Scheme: SchemePoints})
+	if ests := parser.ParseNatural("This should take about a week"); len(ests) != 0 {
+		t.Errorf("ParseNatural() = %v, expected none for story points", ests[0])
+	}
+}
@@ -0,0 +1,183 @@
+package estimate
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Scheme is the estimation scheme a repository uses
+type Scheme string
+
+const (
+	SchemeTime   Scheme = "time"
+	SchemePoints Scheme = "points"
+	SchemeTShirt Scheme = "tshirt"
+)
+
+const (
+	UnitPoints Unit = "points"
+	UnitSize   Unit = "size"
+)
+
+// DefaultPoints are the Fibonacci story points accepted by SchemePoints
+var DefaultPoints = []float64{1, 2, 3, 5, 8, 13, 21}
+
+// DefaultSizes are the T-shirt sizes accepted by SchemeTShirt
+var DefaultSizes = []string{"XS", "S", "M", "L", "XL"}
+
+// schemeSpec describes how estimates of one scheme are written and parsed
+type schemeSpec struct {
+	name     string   // used in the reminder, e.g. "a time estimate"
+	keywords []string // labels that introduce a value besides the locale keywords
+	common   []string // keywords too common in prose to count after other words on a line
+	parse    func(p *Parser, s string) (*Estimate, int, error)
+	format   func(p *Parser) string
+	example  string
+	hint     string
+}
+
+var schemes = map[Scheme]schemeSpec{
+	SchemeTime: {
+		name:    "time",
+		parse:   (*Parser).parseTimeValue,
+		format:  func(*Parser) string { return "Estimate: X days" },
+		example: "Estimate: 3 days",
+		hint: "Supported units: hours (h), days (d), weeks (w), months and sprints.\n" +
+			`Ranges like "Estimate: 2-4 days" are fine too.`,
+	},
+	SchemePoints: {
+		name:     "story point",
+		keywords: []string{"story points", "points"},
+		common:   []string{"points"},
+		parse:    (*Parser).parsePointsValue,
+		format: func(p *Parser) string {
+			return "Estimate: X points (one of " + p.pointList() + ")"
+		},
+		example: "Estimate: 3 points",
+	},
+	SchemeTShirt: {
+		name:     "T-shirt size",
+		keywords: []string{"t-shirt size", "size"},
+		common:   []string{"size"},
+		parse:    (*Parser).parseSizeValue,
+		format: func(p *Parser) string {
+			return "Estimate: SIZE (one of " + strings.Join(p.opts.Sizes, ", ") + ")"
+		},
+		example: "Estimate: M",
+	},
+}
+
+// ParseScheme validates a scheme name, an empty name means SchemeTime
+func ParseScheme(name string) (Scheme, error) {
+	if name == "" {
+		return SchemeTime, nil
+	}
+	scheme := Scheme(strings.ToLower(name))
+	if _, ok := schemes[scheme]; !ok {
+		return "", fmt.Errorf("unknown estimation scheme %q", name)
+	}
+	return scheme, nil
+}
+
+func keywordPatternFor(spec schemeSpec, locale Locale) *regexp.Regexp {
+	var keywords []string
+	for _, keyword := range slices.Concat(locale.Keywords, spec.keywords) {
+		keywords = append(keywords, regexp.QuoteMeta(keyword))
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(keywords, "|") + `):\s*`)
+}
+
+// midSentence reports whether the keyword matched at start is one of the
+// scheme's common words following other words on its line, a revised
+// marker aside
+func (p *Parser) midSentence(text string, start int) bool {
+	keyword := text[start : start+strings.IndexByte(text[start:], ':')]
+	if !slices.ContainsFunc(p.spec.common, func(common string) bool { return strings.EqualFold(keyword, common) }) {
+		return false
+	}
+
+	lineStart := strings.LastIndexByte(text[:start], '\n') + 1
+	before := text[lineStart:start]
+	if m := revisedPattern.FindStringIndex(before); m != nil {
+		before = before[:m[0]]
+	}
+	return strings.ContainsFunc(before, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) })
+}
+
+// parsePointsValue parses a story point value such as "5" or "5 points"
+// that is one of the allowed point values
+func (p *Parser) parsePointsValue(s string) (*Estimate, int, error) {
+	tokens := p.lex(s)
+	if n, ok := placeholder(tokens); ok {
+		return nil, n, invalid("it looks like the placeholder from the example, replace it with a number")
+	}
+	if len(tokens) == 0 || tokens[0].kind != tokenNumber {
+		return nil, 0, ErrNoEstimate
+	}
+	value, end := tokens[0].num, tokens[0].end
+
+	if len(tokens) > 1 && tokens[1].kind == tokenWord {
+		if !slices.Contains(p.locale.PointWords, tokens[1].text) {
+			return nil, 0, ErrNoEstimate
+		}
+		end = tokens[1].end
+	}
+
+	if !slices.Contains(p.opts.Points, value) {
+		return nil, end, invalid("%s is not one of the allowed story points (%s)", formatNumber(value), p.pointList())
+	}
+	return &Estimate{Value: value, Low: value, High: value, Unit: UnitPoints, Confidence: 1}, end, nil
+}
+
+// parseSizeValue parses a T-shirt size such as "M" that is one of the
+// allowed sizes
+func (p *Parser) parseSizeValue(s string) (*Estimate, int, error) {
+	tokens := p.lex(s)
+	if len(tokens) == 0 || tokens[0].kind != tokenWord {
+		if n, ok := placeholder(tokens); ok {
+			return nil, n, invalid("it looks like the placeholder from the example, replace it with a size")
+		}
+		return nil, 0, ErrNoEstimate
+	}
+
+	for i, size := range p.opts.Sizes {
+		if strings.EqualFold(tokens[0].text, size) {
+			// sizes are ordered smallest first, so their position doubles
+			// as a value that can be compared
+			value := float64(i + 1)
+			return &Estimate{Value: value, Low: value, High: value, Unit: UnitSize, Size: size, Confidence: 1}, tokens[0].end, nil
+		}
+	}
+	return nil, tokens[0].end, invalid("%q is not one of the allowed sizes (%s)", tokens[0].text, strings.Join(p.opts.Sizes, ", "))
+}
+
+func (p *Parser) pointList() string {
+	values := make([]string, len(p.opts.Points))
+	for i, value := range p.opts.Points {
+		values[i] = formatNumber(value)
+	}
+	return strings.Join(values, ", ")
+}
+
+// Name describes the kind of estimate the parser expects, e.g. "time"
+func (p *Parser) Name() string {
+	return p.spec.name
+}
+
+// Format describes how an estimate should be written, e.g. "Estimate: X days"
+func (p *Parser) Format() string {
+	return p.spec.format(p)
+}
+
+// Example is a valid estimate in the parser's scheme
+func (p *Parser) Example() string {
+	return p.spec.example
+}
+
+// Hint is extra help on writing estimates, it may be empty
+func (p *Parser) Hint() string {
+	return p.spec.hint
+}
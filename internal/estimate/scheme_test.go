@@ -0,0 +1,155 @@
+package estimate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse_Schemes(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		body   string
+		found  bool
+		value  float64
+		result string
+	}{
+		{
+			name:   "Story points",
+			opts:   Options{Scheme: SchemePoints},
+			body:   "Estimate: 5 points",
+			found:  true,
+			value:  5,
+			result: "5 points",
+		},
+		{
+			name:   "Story points keyword without unit",
+			opts:   Options{Scheme: SchemePoints},
+			body:   "Story points: 8",
+			found:  true,
+			value:  8,
+			result: "8 points",
+		},
+		{
+			name:  "Story points outside the allowed set",
+			opts:  Options{Scheme: SchemePoints},
+			body:  "Estimate: 4 points",
+			found: false,
+		},
+		{
+			name:   "Custom story points",
+			opts:   Options{Scheme: SchemePoints, Points: []float64{0.5, 1, 4}},
+			body:   "Estimate: 4 pts",
+			found:  true,
+			value:  4,
+			result: "4 points",
+		},
+		{
+			name:  "Time estimate under story points scheme",
+			opts:  Options{Scheme: SchemePoints},
+			body:  "Estimate: 3 days",
+			found: false,
+		},
+		{
+			name:   "T-shirt size",
+			opts:   Options{Scheme: SchemeTShirt},
+			body:   "Size: m",
+			found:  true,
+			value:  3,
+			result: "size M",
+		},
+		{
+			name:   "T-shirt size with estimate keyword",
+			opts:   Options{Scheme: SchemeTShirt},
+			body:   "Estimate: XL",
+			found:  true,
+			value:  5,
+			result: "size XL",
+		},
+		{
+			name:   "T-shirt size in a list",
+			opts:   Options{Scheme: SchemeTShirt},
+			body:   "- Size: S",
+			found:  true,
+			value:  2,
+			result: "size S",
+		},
+		{
+			name:   "Revised T-shirt size",
+			opts:   Options{Scheme: SchemeTShirt},
+			body:   "Revised size: L",
+			found:  true,
+			value:  4,
+			result: "size L",
+		},
+		{
+			name:  "Unknown T-shirt size",
+			opts:  Options{Scheme: SchemeTShirt},
+			body:  "Estimate: XXXL",
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := NewParser(tt.opts).Parse(tt.body)
+			ok := err == nil
+			if ok != tt.found {
+				t.Fatalf("Parse() found = %v, expected %v for body: %s", ok, tt.found, tt.body)
+			}
+			if !ok {
+				return
+			}
+			if est.Value != tt.value || est.String() != tt.result {
+				t.Errorf("Parse() = %s (%v), expected %s (%v)", est, est.Value, tt.result, tt.value)
+			}
+		})
+	}
+}
+
+func TestParseScheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Scheme
+		valid    bool
+	}{
+		{name: "", expected: SchemeTime, valid: true},
+		{name: "Points", expected: SchemePoints, valid: true},
+		{name: "tshirt", expected: SchemeTShirt, valid: true},
+		{name: "hours", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, err := ParseScheme(tt.name)
+			if (err == nil) != tt.valid {
+				t.Fatalf("ParseScheme(%q) error = %v, expected valid %v", tt.name, err, tt.valid)
+			}
+			if scheme != tt.expected {
+				t.Errorf("ParseScheme(%q) = %q, expected %q", tt.name, scheme, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParse_KeywordsInProse(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		body string
+	}{
+		{name: "size mid sentence", opts: Options{Scheme: SchemeTShirt}, body: "Increase the batch size: large uploads fail"},
+		{name: "size inside a word", opts: Options{Scheme: SchemeTShirt}, body: "Filesize: L"},
+		{name: "points mid sentence", opts: Options{Scheme: SchemePoints}, body: "The main points: 3 of them are open"},
+		{name: "estimate inside a word", opts: Options{}, body: "underestimate: 2 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := NewParser(tt.opts).Parse(tt.body)
+			if !errors.Is(err, ErrNoEstimate) {
+				t.Errorf("Parse(%q) = %v, %v, expected ErrNoEstimate", tt.body, est, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package estimate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SourceTaskList is the total of the estimates of an issue's task list
+const SourceTaskList Source = "task list"
+
+// Task is a single item of a markdown task list
+type Task struct {
+	Text     string    // task description without its estimate
+	Done     bool      // the box is checked
+	Line     int       // 1-based line number of the task
+	Estimate *Estimate // nil when the task has no estimate
+}
+
+// TaskList is every task list item of an issue body and the total of
+// their estimates
+type TaskList struct {
+	Tasks []Task
+	Total *Estimate // nil when no task has an estimate
+}
+
+// Estimated returns the number of tasks that have an estimate
+func (l *TaskList) Estimated() int {
+	n := 0
+	for _, task := range l.Tasks {
+		if task.Estimate != nil {
+			n++
+		}
+	}
+	return n
+}
+
+var (
+	// matches a task list item such as "- [ ] API endpoint (1d)"
+	taskPattern = regexp.MustCompile(`(?m)^[ \t]*[-*+][ \t]+\[([ xX])\][ \t]+(.*?)[ \t\r]*$`)
+	// matches a parenthesized part of a task that may hold its estimate
+	taskEstimatePattern = regexp.MustCompile(`\(([^()]+)\)`)
+)
+
+// ParseTasks returns the task list items in the visible prose of body with
+// their estimates, written in parentheses like "- [ ] UI (2d)", and their
+// total. Story points are added up, T-shirt sizes have no total. Task
+// estimates are not checked against the minimum and maximum, the total is,
+// and a total out of bounds is returned as an *InvalidError.
+func (p *Parser) ParseTasks(body string) (*TaskList, error) {
+	body = VisibleText(body)
+
+	// a small task may well be shorter than the minimum for a whole issue
+	unbounded := *p
+	unbounded.opts.MinDuration, unbounded.opts.MaxDuration = 0, 0
+
+	list := &TaskList{}
+	var ests []*Estimate
+	for _, loc := range taskPattern.FindAllStringSubmatchIndex(body, -1) {
+		task := Task{
+			Text: body[loc[4]:loc[5]],
+			Done: body[loc[2]:loc[3]] != " ",
+			Line: lineAt(body, loc[0]),
+		}
+
+		// the last parenthesized estimate counts, "(backend) (2d)" is 2 days
+		matches := taskEstimatePattern.FindAllStringSubmatchIndex(task.Text, -1)
+		for i := len(matches) - 1; i >= 0; i-- {
+			m := matches[i]
+			est, err := unbounded.ParseValue(task.Text[m[2]:m[3]])
+			if err != nil {
+				continue
+			}
+
+			est.Start, est.End = loc[4]+m[0], loc[4]+m[1]
+			est.Text = task.Text[m[0]:m[1]]
+			est.Line = task.Line
+			est.Source = SourceTaskList
+			task.Estimate = est
+			task.Text = strings.TrimSpace(task.Text[:m[0]] + task.Text[m[1]:])
+			ests = append(ests, est)
+			break
+		}
+
+		list.Tasks = append(list.Tasks, task)
+	}
+
+	if len(ests) == 0 || p.opts.Scheme == SchemeTShirt {
+		return list, nil
+	}
+
+	list.Total = p.sum(ests)
+	if p.opts.Scheme == SchemeTime {
+		if err := p.checkBounds(list.Total); err != nil {
+			var invalidErr *InvalidError
+			if errors.As(err, &invalidErr) {
+				invalidErr.Text = list.Total.Text
+			}
+			return list, err
+		}
+	}
+	return list, nil
+}
+
+// sum adds up estimates, time estimates in different units are totalled
+// in days, or hours when under a day
+func (p *Parser) sum(ests []*Estimate) *Estimate {
+	total := &Estimate{Unit: ests[0].Unit, Source: SourceTaskList, Confidence: 1}
+	sameUnit := true
+	for _, est := range ests {
+		sameUnit = sameUnit && est.Unit == total.Unit
+		total.Value += est.Value
+		total.Low += est.Low
+		total.High += est.High
+		total.Duration += est.Duration
+		total.LowDuration += est.LowDuration
+		total.HighDuration += est.HighDuration
+		total.Approximate = total.Approximate || est.Approximate
+	}
+
+	if p.opts.Scheme == SchemeTime && !sameUnit {
+		total.Unit = UnitDays
+		if total.Duration < p.opts.WorkdayLength {
+			total.Unit = UnitHours
+		}
+		per := float64(p.Duration(1, total.Unit))
+		total.Value = float64(total.Duration) / per
+		total.Low = float64(total.LowDuration) / per
+		total.High = float64(total.HighDuration) / per
+	}
+
+	total.Text = fmt.Sprintf("task list total of %s", total)
+	return total
+}
@@ -0,0 +1,71 @@
+package estimate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParser_ParseTasks(t *testing.T) {
+	body := "Work:\n" +
+		"- [ ] API endpoint (1d)\n" +
+		"- [x] UI (backend first) (2d)\n" +
+		"- [ ] Docs\n" +
+		"```\n- [ ] Hidden (5d)\n```\n" +
+		"* [ ] Review (4h)"
+
+	list, err := NewParser(DefaultOptions()).ParseTasks(body)
+	if err != nil {
+		t.Fatalf("ParseTasks() error = %v", err)
+	}
+
+	expected := []struct {
+		text     string
+		done     bool
+		estimate string
+	}{
+		{text: "API endpoint", estimate: "1 days"},
+		{text: "UI (backend first)", done: true, estimate: "2 days"},
+		{text: "Docs"},
+		{text: "Review", estimate: "4 hours"},
+	}
+	if len(list.Tasks) != len(expected) {
+		t.Fatalf("ParseTasks() found %d tasks, expected %d", len(list.Tasks), len(expected))
+	}
+	for i, task := range list.Tasks {
+		est := ""
+		if task.Estimate != nil {
+			est = task.Estimate.String()
+		}
+		if task.Text != expected[i].text || task.Done != expected[i].done || est != expected[i].estimate {
+			t.Errorf("task %d = %q done=%v estimate=%q, expected %q done=%v estimate=%q",
+				i, task.Text, task.Done, est, expected[i].text, expected[i].done, expected[i].estimate)
+		}
+	}
+
+	if list.Estimated() != 3 {
+		t.Errorf("Estimated() = %d, expected 3", list.Estimated())
+	}
+	if list.Total == nil || list.Total.String() != "3.5 days" {
+		t.Errorf("Total = %v, expected 3.5 days", list.Total)
+	}
+}
+
+func TestParser_ParseTasks_Points(t *testing.T) {
+	list, err := NewParser(Options{Scheme: SchemePoints}).ParseTasks("- [ ] API (3 points)\n- [ ] UI (5)")
+	if err != nil {
+		t.Fatalf("ParseTasks() error = %v", err)
+	}
+	if list.Total == nil || list.Total.String() != "8 points" {
+		t.Errorf("Total = %v, expected 8 points", list.Total)
+	}
+}
+
+func TestParser_ParseTasks_TotalOutOfBounds(t *testing.T) {
+	parser := NewParser(Options{MaxDuration: NewParser(Options{}).Duration(5, UnitDays)})
+
+	_, err := parser.ParseTasks("- [ ] API (3d)\n- [ ] UI (3d)")
+	var invalidErr *InvalidError
+	if !errors.As(err, &invalidErr) {
+		t.Fatalf("ParseTasks() error = %v, expected an *InvalidError", err)
+	}
+}
@@ -0,0 +1,37 @@
+package estimate
+
+import (
+	"errors"
+)
+
+// ParseTitle returns the estimate in an issue title written with one of
+// the parser's title patterns, such as "[3d] Fix login redirect" for "[*]"
+// or "Fix login (est. 2h)" for "(est. *)". The value is parsed with the
+// same rules as a body estimate. If no match holds a valid estimate the
+// first rejected one is returned as an *InvalidError, or ErrNoEstimate.
+func (p *Parser) ParseTitle(title string) (*Estimate, error) {
+	var rejected error
+	for _, pattern := range p.titlePatterns {
+		for _, loc := range pattern.FindAllStringSubmatchIndex(title, -1) {
+			est, err := p.ParseValue(title[loc[2]:loc[3]])
+
+			var invalidErr *InvalidError
+			switch {
+			case err == nil:
+				est.Start, est.End = loc[0], loc[1]
+				est.Text = title[loc[0]:loc[1]]
+				est.Source = SourceTitle
+				return est, nil
+			case errors.As(err, &invalidErr) && rejected == nil:
+				invalidErr.Start, invalidErr.End = loc[0], loc[1]
+				invalidErr.Text = title[loc[0]:loc[1]]
+				rejected = invalidErr
+			}
+		}
+	}
+
+	if rejected != nil {
+		return nil, rejected
+	}
+	return nil, ErrNoEstimate
+}
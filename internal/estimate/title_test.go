@@ -0,0 +1,63 @@
+package estimate
+
+import (
+	"testing"
+)
+
+func TestParser_ParseTitle(t *testing.T) {
+	parser := NewParser(Options{TitlePatterns: []string{"[*]", "(est. *)"}})
+
+	tests := []struct {
+		name     string
+		title    string
+		found    bool
+		expected string
+		text     string
+	}{
+		{
+			name:     "Bracket prefix",
+			title:    "[3d] Fix login redirect",
+			found:    true,
+			expected: "3 days",
+			text:     "[3d]",
+		},
+		{
+			name:     "Parenthesized suffix",
+			title:    "Fix login (est. 2h)",
+			found:    true,
+			expected: "2 hours",
+			text:     "(est. 2h)",
+		},
+		{
+			name:     "Other bracket before the estimate",
+			title:    "[bug] [1-2 weeks] Rewrite auth",
+			found:    true,
+			expected: "1-2 weeks",
+			text:     "[1-2 weeks]",
+		},
+		{
+			name:  "No estimate",
+			title: "[bug] Fix login redirect",
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := parser.ParseTitle(tt.title)
+			if found := err == nil; found != tt.found {
+				t.Fatalf("ParseTitle() found = %v, expected %v for title: %s", found, tt.found, tt.title)
+			}
+			if !tt.found {
+				return
+			}
+			if est.String() != tt.expected || est.Text != tt.text {
+				t.Errorf("ParseTitle() = %s from %q, expected %s from %q", est, est.Text, tt.expected, tt.text)
+			}
+		})
+	}
+
+	if _, err := NewParser(DefaultOptions()).ParseTitle("[3d] Fix login redirect"); err == nil {
+		t.Error("ParseTitle() found an estimate without title patterns")
+	}
+}
@@ -0,0 +1,240 @@
+package estimate
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenWord
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenKind
+	text  string // lowercased for words, normalized for symbols
+	num   float64
+	start int
+	end   int
+}
+
+// lex splits a single line into numbers, words and symbols, keeping the
+// byte offsets of each token so matches can be mapped back to the input
+func (p *Parser) lex(s string) []token {
+	var tokens []token
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\n':
+			return tokens
+		case unicode.IsSpace(r):
+			i += size
+		case r >= '0' && r <= '9':
+			end := scanNumber(s, i, p.locale.DecimalComma)
+			num, err := strconv.ParseFloat(strings.Replace(s[i:end], ",", ".", 1), 64)
+			if err != nil {
+				return tokens
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: s[i:end], num: num, start: i, end: end})
+			i = end
+		case unicode.IsLetter(r):
+			end := i
+			for end < len(s) {
+				r, size := utf8.DecodeRuneInString(s[end:])
+				if !unicode.IsLetter(r) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: strings.ToLower(s[i:end]), start: i, end: end})
+			i = end
+		case strings.HasPrefix(s[i:], "+/-"):
+			tokens = append(tokens, token{kind: tokenSymbol, text: "±", start: i, end: i + 3})
+			i += 3
+		default:
+			text := string(r)
+			if r == '–' || r == '—' {
+				text = "-"
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: text, start: i, end: i + size})
+			i += size
+		}
+	}
+	return tokens
+}
+
+func scanNumber(s string, start int, decimalComma bool) int {
+	end := start
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	if end+1 < len(s) && (s[end] == '.' || decimalComma && s[end] == ',') && s[end+1] >= '0' && s[end+1] <= '9' {
+		end++
+		for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+			end++
+		}
+	}
+	return end
+}
+
+// quantity is a number with the unit written next to it, if any
+type quantity struct {
+	value float64
+	unit  Unit
+	set   bool // unit was written explicitly
+}
+
+// valueParser walks the tokens of an estimate value such as "2-4 days",
+// "3d ± 1d" or "~5 days"
+type valueParser struct {
+	p      *Parser
+	tokens []token
+	pos    int
+}
+
+func (v *valueParser) peek() (token, bool) {
+	if v.pos >= len(v.tokens) {
+		return token{}, false
+	}
+	return v.tokens[v.pos], true
+}
+
+func (v *valueParser) acceptSymbol(symbols ...string) bool {
+	tok, ok := v.peek()
+	if !ok || tok.kind != tokenSymbol {
+		return false
+	}
+	for _, symbol := range symbols {
+		if tok.text == symbol {
+			v.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (v *valueParser) acceptWord(words []string) bool {
+	tok, ok := v.peek()
+	if !ok || tok.kind != tokenWord || !slices.Contains(words, tok.text) {
+		return false
+	}
+	v.pos++
+	return true
+}
+
+func (v *valueParser) quantity() (quantity, bool) {
+	tok, ok := v.peek()
+	if !ok || tok.kind != tokenNumber {
+		return quantity{}, false
+	}
+	v.pos++
+
+	q := quantity{value: tok.num}
+	if next, ok := v.peek(); ok && next.kind == tokenWord {
+		if unit, ok := v.p.locale.Units[next.text]; ok {
+			q.unit = unit
+			q.set = true
+			v.pos++
+		}
+	}
+	return q, true
+}
+
+// parseTimeValue parses the time estimate at the start of s and returns it
+// along with the number of bytes it covers
+func (p *Parser) parseTimeValue(s string) (*Estimate, int, error) {
+	v := &valueParser{p: p, tokens: p.lex(s)}
+	if n, ok := placeholder(v.tokens); ok {
+		return nil, n, invalid("it looks like the placeholder from the example, replace it with a number")
+	}
+
+	approximate := v.acceptSymbol("~", "≈")
+	if !approximate && v.acceptWord(p.locale.ApproxWords) {
+		v.acceptSymbol(".")
+		approximate = true
+	}
+
+	first, ok := v.quantity()
+	if !ok {
+		return nil, 0, ErrNoEstimate
+	}
+	end := v.pos
+
+	low, high := first, first
+	var tolerance *quantity
+
+	// a range or tolerance only counts if a number follows the separator,
+	// otherwise "3 days - blocked on review" is still a plain 3 days
+	mark := v.pos
+	if v.acceptSymbol("-") || v.acceptWord(p.locale.RangeWords) {
+		if second, ok := v.quantity(); ok {
+			high = second
+			end = v.pos
+		} else {
+			v.pos = mark
+		}
+	} else if v.acceptSymbol("±") {
+		if second, ok := v.quantity(); ok {
+			tolerance = &second
+			end = v.pos
+		} else {
+			v.pos = mark
+		}
+	}
+
+	var unit Unit
+	switch {
+	case high.set:
+		unit = high.unit
+	case low.set:
+		unit = low.unit
+	case tolerance != nil && tolerance.set:
+		unit = tolerance.unit
+	default:
+		if next, ok := v.peek(); ok && next.kind == tokenWord {
+			return nil, next.end, invalid("%q is not a known unit", next.text)
+		}
+		return nil, v.tokens[end-1].end, invalid("it is missing a unit such as days")
+	}
+	for _, q := range []*quantity{&low, &high, tolerance} {
+		if q != nil && !q.set {
+			q.unit = unit
+		}
+	}
+
+	est := &Estimate{Unit: unit, Approximate: approximate, Confidence: 1}
+	est.Low = p.convert(low.value, low.unit, unit)
+	est.High = p.convert(high.value, high.unit, unit)
+	est.Value = (est.Low + est.High) / 2
+	if tolerance != nil {
+		delta := p.convert(tolerance.value, tolerance.unit, unit)
+		est.Low, est.High = est.Value-delta, est.Value+delta
+	}
+	if est.Low > est.High {
+		est.Low, est.High = est.High, est.Low
+	}
+
+	est.Duration = p.Duration(est.Value, unit)
+	est.LowDuration = p.Duration(est.Low, unit)
+	est.HighDuration = p.Duration(est.High, unit)
+
+	n := v.tokens[end-1].end
+	if err := p.checkBounds(est); err != nil {
+		return nil, n, err
+	}
+	return est, n, nil
+}
+
+// convert expresses value written in unit "from" in unit "to"
+func (p *Parser) convert(value float64, from, to Unit) float64 {
+	if from == to {
+		return value
+	}
+	return value * float64(p.Duration(1, from)) / float64(p.Duration(1, to))
+}
@@ -3,39 +3,76 @@ package github
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v74/github"
 	"github.com/taman9333/issue-estimate-reminder/internal/config"
 )
 
+// installation tokens last an hour, they are renewed a little early so
+// one doesn't expire halfway through handling an event
+const tokenRenewal = 5 * time.Minute
+
 type Client struct {
 	config *config.Config
 	auth   *Auth
+
+	mu     sync.Mutex
+	tokens map[int64]*github.InstallationToken
 }
 
 func New(cfg *config.Config) *Client {
 	return &Client{
 		config: cfg,
 		auth:   NewAuth(cfg),
+		tokens: map[int64]*github.InstallationToken{},
 	}
 }
 
-func (c *Client) CreateInstallationClient(installationID int64) (*github.Client, error) {
+// CreateAppClient returns a client authenticated as the app itself, for
+// the endpoints under /app
+func (c *Client) CreateAppClient() (*github.Client, error) {
 	token, err := c.auth.GenerateJWT()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate JWT: %v", err)
 	}
 
-	appClient := github.NewClient(nil).WithAuthToken(token)
+	return github.NewClient(nil).WithAuthToken(token), nil
+}
 
-	installationToken, _, err := appClient.Apps.CreateInstallationToken(
-		context.Background(),
-		installationID,
-		&github.InstallationTokenOptions{},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create installation token: %v", err)
+// CreateInstallationClient returns a client authenticated as an
+// installation, reusing its token until it is about to expire
+func (c *Client) CreateInstallationClient(installationID int64) (*github.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	token, ok := c.tokens[installationID]
+	if !ok || time.Until(token.GetExpiresAt().Time) < tokenRenewal {
+		appClient, err := c.CreateAppClient()
+		if err != nil {
+			return nil, err
+		}
+
+		token, _, err = appClient.Apps.CreateInstallationToken(
+			context.Background(),
+			installationID,
+			&github.InstallationTokenOptions{},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create installation token: %v", err)
+		}
+		c.tokens[installationID] = token
 	}
 
-	return github.NewClient(nil).WithAuthToken(installationToken.GetToken()), nil
+	return github.NewClient(nil).WithAuthToken(token.GetToken()), nil
+}
+
+// ForgetInstallation drops the cached token of an installation that was
+// deleted or suspended
+func (c *Client) ForgetInstallation(installationID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.tokens, installationID)
 }
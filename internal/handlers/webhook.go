@@ -5,6 +5,8 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/google/go-github/v74/github"
 	"github.com/taman9333/issue-estimate-reminder/internal/app"
@@ -41,12 +43,88 @@ func (h *WebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	eventType := r.Header.Get("X-GitHub-Event")
 	log.Printf("Received %s event", eventType)
 
-	if eventType != "issues" {
+	switch eventType {
+	case "ping":
+		h.handlePing(w, body)
+	case "issues":
+		h.handleIssues(w, body)
+	case "issue_comment":
+		h.handleIssueComment(w, body)
+	case "pull_request":
+		h.handlePullRequest(w, body)
+	case "check_run":
+		h.handleCheckRun(w, body)
+	case "installation":
+		h.handleInstallation(w, body)
+	case "installation_repositories":
+		h.handleInstallationRepositories(w, body)
+	default:
 		log.Printf("Ignoring %s event", eventType)
 		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// pingResponse tells whoever configured the webhook which app answered and
+// whether the hook sends every event it needs
+type pingResponse struct {
+	Version       string   `json:"version"`
+	Events        []string `json:"events"`
+	MissingEvents []string `json:"missing_events"`
+}
+
+func (h *WebhookHandler) handlePing(w http.ResponseWriter, body []byte) {
+	var payload github.PingEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		log.Printf("Error unmarshaling payload: %v", err)
+		http.Error(w, "Error parsing payload", http.StatusBadRequest)
 		return
 	}
 
+	events := payload.GetHook().Events
+	missing := missingEvents(h.app.RequiredEvents(), events)
+	if len(missing) > 0 {
+		log.Printf("Warning: webhook %d is not subscribed to %s, the app will not see them",
+			payload.GetHookID(), strings.Join(missing, ", "))
+	} else {
+		log.Printf("Webhook %d is subscribed to every event the app needs", payload.GetHookID())
+	}
+
+	response := pingResponse{
+		Version:       app.Version,
+		Events:        events,
+		MissingEvents: missing,
+	}
+	if response.Events == nil {
+		response.Events = []string{}
+	}
+	if response.MissingEvents == nil {
+		response.MissingEvents = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error writing ping response: %v", err)
+	}
+}
+
+// missingEvents returns the required events a hook isn't subscribed to,
+// "*" subscribes to all of them
+func missingEvents(required, subscribed []string) []string {
+	if slices.Contains(subscribed, "*") {
+		return nil
+	}
+
+	var missing []string
+	for _, event := range required {
+		if !slices.Contains(subscribed, event) {
+			missing = append(missing, event)
+		}
+	}
+	return missing
+}
+
+func (h *WebhookHandler) handleIssues(w http.ResponseWriter, body []byte) {
 	var payload github.IssuesEvent
 	if err := json.Unmarshal(body, &payload); err != nil {
 		log.Printf("Error unmarshaling payload: %v", err)
@@ -54,18 +132,136 @@ func (h *WebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if payload.GetAction() != "opened" {
+	var err error
+	switch payload.GetAction() {
+	case "opened":
+		log.Printf("Processing new issue #%d: %s",
+			payload.GetIssue().GetNumber(),
+			payload.GetIssue().GetTitle())
+		err = h.app.HandleIssueOpened(&payload)
+	case "edited":
+		err = h.app.HandleIssueEdited(&payload)
+	case "reopened":
+		err = h.app.HandleIssueReopened(&payload)
+	case "labeled":
+		err = h.app.HandleIssueLabeled(&payload)
+	case "unlabeled":
+		err = h.app.HandleIssueUnlabeled(&payload)
+	case "transferred":
+		var transfer app.IssueTransferredEvent
+		if err := json.Unmarshal(body, &transfer); err != nil {
+			log.Printf("Error unmarshaling payload: %v", err)
+			http.Error(w, "Error parsing payload", http.StatusBadRequest)
+			return
+		}
+		err = h.app.HandleIssueTransferred(&transfer)
+	default:
 		log.Printf("Ignoring issues %s action", payload.GetAction())
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	log.Printf("Processing new issue #%d: %s",
-		payload.GetIssue().GetNumber(),
-		payload.GetIssue().GetTitle())
+	if err != nil {
+		log.Printf("Error handling issues %s event: %v", payload.GetAction(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) handleIssueComment(w http.ResponseWriter, body []byte) {
+	var payload github.IssueCommentEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		log.Printf("Error unmarshaling payload: %v", err)
+		http.Error(w, "Error parsing payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.app.HandleIssueComment(&payload); err != nil {
+		log.Printf("Error handling issue_comment %s event: %v", payload.GetAction(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) handlePullRequest(w http.ResponseWriter, body []byte) {
+	var payload github.PullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		log.Printf("Error unmarshaling payload: %v", err)
+		http.Error(w, "Error parsing payload", http.StatusBadRequest)
+		return
+	}
+
+	switch payload.GetAction() {
+	case "opened", "edited", "reopened", "synchronize", "labeled", "unlabeled":
+	default:
+		log.Printf("Ignoring pull_request %s action", payload.GetAction())
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := h.app.HandlePullRequest(&payload); err != nil {
+		log.Printf("Error handling pull_request %s event: %v", payload.GetAction(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) handleCheckRun(w http.ResponseWriter, body []byte) {
+	var payload github.CheckRunEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		log.Printf("Error unmarshaling payload: %v", err)
+		http.Error(w, "Error parsing payload", http.StatusBadRequest)
+		return
+	}
+
+	if payload.GetAction() != "rerequested" {
+		log.Printf("Ignoring check_run %s action", payload.GetAction())
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := h.app.HandleCheckRunRerequested(&payload); err != nil {
+		log.Printf("Error handling check_run %s event: %v", payload.GetAction(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) handleInstallation(w http.ResponseWriter, body []byte) {
+	var payload github.InstallationEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		log.Printf("Error unmarshaling payload: %v", err)
+		http.Error(w, "Error parsing payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.app.HandleInstallation(&payload); err != nil {
+		log.Printf("Error handling installation %s event: %v", payload.GetAction(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) handleInstallationRepositories(w http.ResponseWriter, body []byte) {
+	var payload github.InstallationRepositoriesEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		log.Printf("Error unmarshaling payload: %v", err)
+		http.Error(w, "Error parsing payload", http.StatusBadRequest)
+		return
+	}
 
-	if err := h.app.HandleIssueOpened(&payload); err != nil {
-		log.Printf("Error handling issue opened event: %v", err)
+	if err := h.app.HandleInstallationRepositories(&payload); err != nil {
+		log.Printf("Error handling installation_repositories %s event: %v", payload.GetAction(), err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
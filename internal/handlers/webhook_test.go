@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/taman9333/issue-estimate-reminder/internal/app"
 	"github.com/taman9333/issue-estimate-reminder/test/mocks"
 	"github.com/taman9333/issue-estimate-reminder/test/testutils"
 	"go.uber.org/mock/gomock"
@@ -125,3 +126,390 @@ func TestWebhookHandler_Handle_IgnoreNonOpenedActions(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, recorder.Code)
 }
+
+func TestWebhookHandler_Handle_Actions(t *testing.T) {
+	tests := []struct {
+		action string
+		expect func(mockApp *mocks.MockAppInterface)
+	}{
+		{
+			action: "edited",
+			expect: func(mockApp *mocks.MockAppInterface) {
+				mockApp.EXPECT().HandleIssueEdited(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			action: "reopened",
+			expect: func(mockApp *mocks.MockAppInterface) {
+				mockApp.EXPECT().HandleIssueReopened(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			action: "labeled",
+			expect: func(mockApp *mocks.MockAppInterface) {
+				mockApp.EXPECT().HandleIssueLabeled(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			action: "unlabeled",
+			expect: func(mockApp *mocks.MockAppInterface) {
+				mockApp.EXPECT().HandleIssueUnlabeled(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			action: "transferred",
+			expect: func(mockApp *mocks.MockAppInterface) {
+				mockApp.EXPECT().
+					HandleIssueTransferred(gomock.Any()).
+					DoAndReturn(func(payload *app.IssueTransferredEvent) error {
+						assert.Equal(t, 7, payload.Changes.NewIssue.GetNumber())
+						assert.Equal(t, "acme/web", payload.Changes.NewRepository.GetFullName())
+						return nil
+					}).
+					Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockApp := mocks.NewMockAppInterface(ctrl)
+			handler := NewWebhookHandler(mockApp)
+
+			mockApp.EXPECT().
+				GetWebhookSecret().
+				Return("test_secret")
+			tt.expect(mockApp)
+
+			payload := map[string]interface{}{
+				"action": tt.action,
+				"issue": map[string]interface{}{
+					"number": 1,
+					"title":  "Test Issue",
+					"body":   "Estimate: 2 days",
+				},
+				"changes": map[string]interface{}{
+					"new_issue": map[string]interface{}{
+						"number": 7,
+					},
+					"new_repository": map[string]interface{}{
+						"name":      "web",
+						"full_name": "acme/web",
+					},
+				},
+				"installation": map[string]interface{}{
+					"id": 67890,
+				},
+			}
+
+			payloadBytes, err := json.Marshal(payload)
+			require.NoError(t, err)
+
+			signature := testutils.GenerateWebhookSignature(payloadBytes, "test_secret")
+
+			req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(payloadBytes))
+			req.Header.Set("X-GitHub-Event", "issues")
+			req.Header.Set("X-Hub-Signature-256", signature)
+
+			recorder := httptest.NewRecorder()
+
+			handler.Handle(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func TestWebhookHandler_Handle_IssueComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApp := mocks.NewMockAppInterface(ctrl)
+	handler := NewWebhookHandler(mockApp)
+
+	mockApp.EXPECT().
+		GetWebhookSecret().
+		Return("test_secret")
+
+	mockApp.EXPECT().
+		HandleIssueComment(gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	payload := map[string]interface{}{
+		"action": "created",
+		"issue": map[string]interface{}{
+			"number": 1,
+			"title":  "Test Issue",
+		},
+		"comment": map[string]interface{}{
+			"body":               "Estimate: 2 days",
+			"author_association": "MEMBER",
+		},
+		"installation": map[string]interface{}{
+			"id": 67890,
+		},
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	signature := testutils.GenerateWebhookSignature(payloadBytes, "test_secret")
+
+	req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(payloadBytes))
+	req.Header.Set("X-GitHub-Event", "issue_comment")
+	req.Header.Set("X-Hub-Signature-256", signature)
+
+	recorder := httptest.NewRecorder()
+
+	handler.Handle(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestWebhookHandler_Handle_PullRequest(t *testing.T) {
+	tests := []struct {
+		action  string
+		handled bool
+	}{
+		{action: "opened", handled: true},
+		{action: "synchronize", handled: true},
+		{action: "closed", handled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockApp := mocks.NewMockAppInterface(ctrl)
+			handler := NewWebhookHandler(mockApp)
+
+			mockApp.EXPECT().
+				GetWebhookSecret().
+				Return("test_secret")
+
+			if tt.handled {
+				mockApp.EXPECT().
+					HandlePullRequest(gomock.Any()).
+					Return(nil).
+					Times(1)
+			}
+
+			payload := map[string]interface{}{
+				"action": tt.action,
+				"pull_request": map[string]interface{}{
+					"number": 2,
+					"title":  "Fix login",
+					"body":   "Fixes #1",
+					"head": map[string]interface{}{
+						"sha": "abc123",
+					},
+				},
+				"installation": map[string]interface{}{
+					"id": 67890,
+				},
+			}
+
+			payloadBytes, err := json.Marshal(payload)
+			require.NoError(t, err)
+
+			signature := testutils.GenerateWebhookSignature(payloadBytes, "test_secret")
+
+			req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(payloadBytes))
+			req.Header.Set("X-GitHub-Event", "pull_request")
+			req.Header.Set("X-Hub-Signature-256", signature)
+
+			recorder := httptest.NewRecorder()
+
+			handler.Handle(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func TestWebhookHandler_Handle_Installation(t *testing.T) {
+	tests := []struct {
+		event  string
+		action string
+		expect func(m *mocks.MockAppInterface)
+	}{
+		{
+			event:  "installation",
+			action: "created",
+			expect: func(m *mocks.MockAppInterface) {
+				m.EXPECT().HandleInstallation(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			event:  "installation_repositories",
+			action: "added",
+			expect: func(m *mocks.MockAppInterface) {
+				m.EXPECT().HandleInstallationRepositories(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockApp := mocks.NewMockAppInterface(ctrl)
+			handler := NewWebhookHandler(mockApp)
+
+			mockApp.EXPECT().
+				GetWebhookSecret().
+				Return("test_secret")
+			tt.expect(mockApp)
+
+			payload := map[string]interface{}{
+				"action": tt.action,
+				"installation": map[string]interface{}{
+					"id":      67890,
+					"account": map[string]interface{}{"login": "acme"},
+				},
+				"repositories_added": []map[string]interface{}{
+					{"name": "api", "full_name": "acme/api"},
+				},
+			}
+
+			payloadBytes, err := json.Marshal(payload)
+			require.NoError(t, err)
+
+			signature := testutils.GenerateWebhookSignature(payloadBytes, "test_secret")
+
+			req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(payloadBytes))
+			req.Header.Set("X-GitHub-Event", tt.event)
+			req.Header.Set("X-Hub-Signature-256", signature)
+
+			recorder := httptest.NewRecorder()
+
+			handler.Handle(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func TestWebhookHandler_Handle_Ping(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []string
+		missing []string
+	}{
+		{name: "subscribed", events: []string{"issues", "issue_comment", "pull_request"}, missing: []string{}},
+		{name: "wildcard", events: []string{"*"}, missing: []string{}},
+		{name: "missing", events: []string{"issues"}, missing: []string{"issue_comment", "pull_request"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockApp := mocks.NewMockAppInterface(ctrl)
+			handler := NewWebhookHandler(mockApp)
+
+			mockApp.EXPECT().
+				GetWebhookSecret().
+				Return("test_secret")
+
+			mockApp.EXPECT().
+				RequiredEvents().
+				Return([]string{"issue_comment", "issues", "pull_request"})
+
+			payload := map[string]interface{}{
+				"zen":     "Keep it logically awesome.",
+				"hook_id": 12345,
+				"hook": map[string]interface{}{
+					"id":     12345,
+					"events": tt.events,
+				},
+			}
+
+			payloadBytes, err := json.Marshal(payload)
+			require.NoError(t, err)
+
+			signature := testutils.GenerateWebhookSignature(payloadBytes, "test_secret")
+
+			req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(payloadBytes))
+			req.Header.Set("X-GitHub-Event", "ping")
+			req.Header.Set("X-Hub-Signature-256", signature)
+
+			recorder := httptest.NewRecorder()
+
+			handler.Handle(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+			var response pingResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, "dev", response.Version)
+			assert.Equal(t, tt.events, response.Events)
+			assert.Equal(t, tt.missing, response.MissingEvents)
+		})
+	}
+}
+
+func TestWebhookHandler_Handle_CheckRun(t *testing.T) {
+	tests := []struct {
+		action  string
+		handled bool
+	}{
+		{action: "rerequested", handled: true},
+		{action: "completed", handled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockApp := mocks.NewMockAppInterface(ctrl)
+			handler := NewWebhookHandler(mockApp)
+
+			mockApp.EXPECT().
+				GetWebhookSecret().
+				Return("test_secret")
+
+			if tt.handled {
+				mockApp.EXPECT().
+					HandleCheckRunRerequested(gomock.Any()).
+					Return(nil).
+					Times(1)
+			}
+
+			payload := map[string]interface{}{
+				"action": tt.action,
+				"check_run": map[string]interface{}{
+					"name":     "estimate present",
+					"head_sha": "abc123",
+				},
+				"installation": map[string]interface{}{
+					"id": 67890,
+				},
+			}
+
+			payloadBytes, err := json.Marshal(payload)
+			require.NoError(t, err)
+
+			signature := testutils.GenerateWebhookSignature(payloadBytes, "test_secret")
+
+			req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(payloadBytes))
+			req.Header.Set("X-GitHub-Event", "check_run")
+			req.Header.Set("X-Hub-Signature-256", signature)
+
+			recorder := httptest.NewRecorder()
+
+			handler.Handle(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
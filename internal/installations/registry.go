@@ -0,0 +1,148 @@
+package installations
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Installation is an account the app is installed on and the repositories
+// it was given access to
+type Installation struct {
+	ID        int64
+	Account   string
+	Suspended bool
+	Repos     []string // lowercase "owner/repo", sorted
+}
+
+// Registry keeps track of the app's installations in memory, it is filled
+// from installation webhooks and the startup sync
+type Registry struct {
+	mu            sync.RWMutex
+	installations map[int64]*Installation
+}
+
+func NewRegistry() *Registry {
+	return &Registry{installations: map[int64]*Installation{}}
+}
+
+// Add records an installation with its repositories, replacing what was
+// known about it
+func (r *Registry) Add(id int64, account string, repos []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inst := &Installation{ID: id, Account: account}
+	inst.Repos = mergeRepos(nil, repos)
+	r.installations[id] = inst
+}
+
+// Remove forgets an installation
+func (r *Registry) Remove(id int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.installations, id)
+}
+
+// SetSuspended records whether an installation is suspended and reports
+// whether the installation is known
+func (r *Registry) SetSuspended(id int64, suspended bool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inst, ok := r.installations[id]
+	if ok {
+		inst.Suspended = suspended
+	}
+	return ok
+}
+
+// AddRepos records repositories added to an installation, creating the
+// installation if it isn't known yet
+func (r *Registry) AddRepos(id int64, account string, repos []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inst, ok := r.installations[id]
+	if !ok {
+		inst = &Installation{ID: id, Account: account}
+		r.installations[id] = inst
+	}
+	inst.Repos = mergeRepos(inst.Repos, repos)
+}
+
+// RemoveRepos records repositories removed from an installation
+func (r *Registry) RemoveRepos(id int64, repos []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inst, ok := r.installations[id]
+	if !ok {
+		return
+	}
+	inst.Repos = slices.DeleteFunc(inst.Repos, func(repo string) bool {
+		return slices.ContainsFunc(repos, func(removed string) bool { return strings.EqualFold(repo, removed) })
+	})
+}
+
+// Get returns a copy of an installation
+func (r *Registry) Get(id int64) (Installation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	inst, ok := r.installations[id]
+	if !ok {
+		return Installation{}, false
+	}
+	return copyOf(inst), true
+}
+
+// ForRepo returns the active installation covering a repository given as
+// "owner/repo", suspended installations can't act on it
+func (r *Registry) ForRepo(fullName string) (Installation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fullName = strings.ToLower(fullName)
+	for _, inst := range r.installations {
+		if inst.Suspended {
+			continue
+		}
+		if _, found := slices.BinarySearch(inst.Repos, fullName); found {
+			return copyOf(inst), true
+		}
+	}
+	return Installation{}, false
+}
+
+// List returns copies of all installations ordered by ID
+func (r *Registry) List() []Installation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]Installation, 0, len(r.installations))
+	for _, inst := range r.installations {
+		list = append(list, copyOf(inst))
+	}
+	slices.SortFunc(list, func(a, b Installation) int { return cmp.Compare(a.ID, b.ID) })
+	return list
+}
+
+func copyOf(inst *Installation) Installation {
+	c := *inst
+	c.Repos = slices.Clone(inst.Repos)
+	return c
+}
+
+// mergeRepos adds repos to a sorted list of lowercase names
+func mergeRepos(list, repos []string) []string {
+	for _, repo := range repos {
+		repo = strings.ToLower(repo)
+		if i, found := slices.BinarySearch(list, repo); !found {
+			list = slices.Insert(list, i, repo)
+		}
+	}
+	return list
+}
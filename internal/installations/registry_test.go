@@ -0,0 +1,44 @@
+package installations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.Add(1, "acme", []string{"acme/web", "Acme/API"})
+	r.AddRepos(1, "acme", []string{"acme/docs", "acme/web"})
+	r.AddRepos(2, "globex", []string{"globex/app"})
+	r.RemoveRepos(1, []string{"ACME/web"})
+
+	inst, ok := r.Get(1)
+	if !ok {
+		t.Fatal("Get(1) found no installation")
+	}
+	if expected := []string{"acme/api", "acme/docs"}; !reflect.DeepEqual(inst.Repos, expected) {
+		t.Errorf("Repos = %v, expected %v", inst.Repos, expected)
+	}
+
+	if inst, ok := r.ForRepo("Globex/App"); !ok || inst.ID != 2 {
+		t.Errorf("ForRepo() = %v, %v, expected installation 2", inst.ID, ok)
+	}
+	if _, ok := r.ForRepo("acme/web"); ok {
+		t.Error("ForRepo() found a removed repository")
+	}
+
+	if !r.SetSuspended(2, true) || r.SetSuspended(3, true) {
+		t.Error("SetSuspended() did not report whether the installation is known")
+	}
+	if inst, _ := r.Get(2); !inst.Suspended {
+		t.Error("installation 2 is not suspended")
+	}
+	if _, ok := r.ForRepo("globex/app"); ok {
+		t.Error("ForRepo() found a suspended installation")
+	}
+
+	r.Remove(1)
+	if list := r.List(); len(list) != 1 || list[0].ID != 2 {
+		t.Errorf("List() = %v, expected only installation 2", list)
+	}
+}
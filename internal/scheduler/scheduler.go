@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scheduler runs delayed jobs in memory, at most one per key. Jobs do not
+// survive a restart.
+type Scheduler struct {
+	mu   sync.Mutex
+	jobs map[string]*pending
+}
+
+// pending is a scheduled job and when it runs
+type pending struct {
+	timer *time.Timer
+	due   time.Time
+}
+
+func New() *Scheduler {
+	return &Scheduler{jobs: map[string]*pending{}}
+}
+
+// Schedule runs job after delay, replacing any job pending for key
+func (s *Scheduler) Schedule(key string, delay time.Duration, job func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schedule(key, delay, job)
+}
+
+func (s *Scheduler) schedule(key string, delay time.Duration, job func()) {
+	if p, ok := s.jobs[key]; ok {
+		p.timer.Stop()
+	}
+
+	p := &pending{due: time.Now().Add(delay)}
+	p.timer = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		// a newer job may have replaced this one after it fired
+		if s.jobs[key] == p {
+			delete(s.jobs, key)
+		}
+		s.mu.Unlock()
+		job()
+	})
+	s.jobs[key] = p
+}
+
+// Cancel drops the job pending for key and reports whether there was one
+func (s *Scheduler) Cancel(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.jobs[key]
+	if !ok {
+		return false
+	}
+	p.timer.Stop()
+	delete(s.jobs, key)
+	return true
+}
+
+// Move replaces the job pending for from with job under to, keeping the
+// time it runs at, and reports whether there was one
+func (s *Scheduler) Move(from, to string, job func()) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.jobs[from]
+	if !ok {
+		return false
+	}
+	p.timer.Stop()
+	delete(s.jobs, from)
+	s.schedule(to, max(time.Until(p.due), 0), job)
+	return true
+}
+
+// Pending reports whether a job is scheduled for key
+func (s *Scheduler) Pending(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.jobs[key]
+	return ok
+}
+
+// Keys returns the sorted keys of the pending jobs starting with prefix
+func (s *Scheduler) Keys(prefix string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key := range s.jobs {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
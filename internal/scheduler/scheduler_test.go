@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduler_Schedule(t *testing.T) {
+	s := New()
+	done := make(chan string, 2)
+
+	s.Schedule("a", time.Hour, func() { done <- "old" })
+	s.Schedule("a", time.Millisecond, func() { done <- "new" })
+
+	select {
+	case got := <-done:
+		if got != "new" {
+			t.Errorf("ran %q job, expected the replacement", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+
+	// the job removes itself just before running
+	if s.Pending("a") {
+		t.Error("Pending() = true after the job ran")
+	}
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	s := New()
+	s.Schedule("a", time.Hour, func() {})
+
+	if !s.Pending("a") {
+		t.Error("Pending() = false, expected a scheduled job")
+	}
+	if !s.Cancel("a") {
+		t.Error("Cancel() = false, expected a job to cancel")
+	}
+	if s.Cancel("a") || s.Pending("a") {
+		t.Error("job still pending after Cancel()")
+	}
+}
+
+func TestScheduler_Move(t *testing.T) {
+	s := New()
+	done := make(chan string, 2)
+
+	s.Schedule("a#1@alice", 50*time.Millisecond, func() { done <- "old" })
+	s.Schedule("a#2@bob", time.Hour, func() {})
+
+	if keys := s.Keys("a#1@"); len(keys) != 1 || keys[0] != "a#1@alice" {
+		t.Errorf("Keys() = %v, expected [a#1@alice]", keys)
+	}
+	if !s.Move("a#1@alice", "b#7@alice", func() { done <- "moved" }) {
+		t.Fatal("Move() = false, expected a job to move")
+	}
+	if s.Pending("a#1@alice") || !s.Pending("b#7@alice") {
+		t.Error("job is not pending under its new key")
+	}
+	if s.Move("a#1@alice", "b#7@alice", func() {}) {
+		t.Error("Move() = true for a key with no job")
+	}
+
+	select {
+	case got := <-done:
+		if got != "moved" {
+			t.Errorf("ran %q job, expected the moved one", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("moved job did not run at its original time")
+	}
+}
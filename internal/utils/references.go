@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/taman9333/issue-estimate-reminder/internal/estimate"
+)
+
+// IssueRef is an issue referenced from a pull request, Owner and Repo are
+// empty for a "#12" reference to the same repository
+type IssueRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// matches a closing keyword followed by "#12", "owner/repo#12" or an issue
+// URL, see https://docs.github.com/en/issues/tracking-your-work-with-issues/linking-a-pull-request-to-an-issue
+var closingPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?[ \t]+` +
+	`(?:([\w.-]+)/([\w.-]+)#|https://github\.com/([\w.-]+)/([\w.-]+)/issues/|#)(\d+)\b`)
+
+// ClosingReferences returns the issues a pull request body closes with
+// keywords such as "Fixes #12", in order and without duplicates
+func ClosingReferences(body string) []IssueRef {
+	body = estimate.VisibleText(body)
+
+	var refs []IssueRef
+	seen := map[IssueRef]bool{}
+	for _, m := range closingPattern.FindAllStringSubmatch(body, -1) {
+		number, err := strconv.Atoi(m[5])
+		if err != nil {
+			continue
+		}
+
+		ref := IssueRef{Owner: m[1] + m[3], Repo: m[2] + m[4], Number: number}
+		ref.Owner, ref.Repo = strings.ToLower(ref.Owner), strings.ToLower(ref.Repo)
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
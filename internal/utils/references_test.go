@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClosingReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []IssueRef
+	}{
+		{
+			name:     "Same repository",
+			body:     "Fixes #12",
+			expected: []IssueRef{{Number: 12}},
+		},
+		{
+			name:     "Keyword variants",
+			body:     "closes #1, Resolved: #2 and fixed #3",
+			expected: []IssueRef{{Number: 1}, {Number: 2}, {Number: 3}},
+		},
+		{
+			name:     "Other repository",
+			body:     "Resolves Acme/API#7",
+			expected: []IssueRef{{Owner: "acme", Repo: "api", Number: 7}},
+		},
+		{
+			name:     "Issue URL",
+			body:     "Closes https://github.com/acme/web/issues/9",
+			expected: []IssueRef{{Owner: "acme", Repo: "web", Number: 9}},
+		},
+		{
+			name:     "Duplicates",
+			body:     "Fixes #4\nAlso fixes #4",
+			expected: []IssueRef{{Number: 4}},
+		},
+		{
+			name: "Mentions without keyword",
+			body: "Related to #5, see `fixes #6`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs := ClosingReferences(tt.body)
+			if !reflect.DeepEqual(refs, tt.expected) {
+				t.Errorf("ClosingReferences() = %v, expected %v", refs, tt.expected)
+			}
+		})
+	}
+}
@@ -4,14 +4,14 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
-	"regexp"
 	"strings"
+
+	"github.com/taman9333/issue-estimate-reminder/internal/estimate"
 )
 
 func HasEstimate(body string) bool {
-	// check for "Estimate: X days" format (case insensitive)
-	estimatePattern := regexp.MustCompile(`(?i)estimate:\s*\d+(?:\.\d+)?\s*days?`)
-	return estimatePattern.MatchString(body)
+	_, err := estimate.Parse(body)
+	return err == nil
 }
 
 func VerifyWebhookSignature(payload []byte, signature, secret string) bool {
@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	github "github.com/google/go-github/v74/github"
+	app "github.com/taman9333/issue-estimate-reminder/internal/app"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -54,6 +55,90 @@ func (mr *MockAppInterfaceMockRecorder) GetWebhookSecret() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSecret", reflect.TypeOf((*MockAppInterface)(nil).GetWebhookSecret))
 }
 
+// HandleCheckRunRerequested mocks base method.
+func (m *MockAppInterface) HandleCheckRunRerequested(payload *github.CheckRunEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleCheckRunRerequested", payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleCheckRunRerequested indicates an expected call of HandleCheckRunRerequested.
+func (mr *MockAppInterfaceMockRecorder) HandleCheckRunRerequested(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleCheckRunRerequested", reflect.TypeOf((*MockAppInterface)(nil).HandleCheckRunRerequested), payload)
+}
+
+// HandleInstallation mocks base method.
+func (m *MockAppInterface) HandleInstallation(payload *github.InstallationEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleInstallation", payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleInstallation indicates an expected call of HandleInstallation.
+func (mr *MockAppInterfaceMockRecorder) HandleInstallation(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleInstallation", reflect.TypeOf((*MockAppInterface)(nil).HandleInstallation), payload)
+}
+
+// HandleInstallationRepositories mocks base method.
+func (m *MockAppInterface) HandleInstallationRepositories(payload *github.InstallationRepositoriesEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleInstallationRepositories", payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleInstallationRepositories indicates an expected call of HandleInstallationRepositories.
+func (mr *MockAppInterfaceMockRecorder) HandleInstallationRepositories(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleInstallationRepositories", reflect.TypeOf((*MockAppInterface)(nil).HandleInstallationRepositories), payload)
+}
+
+// HandleIssueComment mocks base method.
+func (m *MockAppInterface) HandleIssueComment(payload *github.IssueCommentEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleIssueComment", payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleIssueComment indicates an expected call of HandleIssueComment.
+func (mr *MockAppInterfaceMockRecorder) HandleIssueComment(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleIssueComment", reflect.TypeOf((*MockAppInterface)(nil).HandleIssueComment), payload)
+}
+
+// HandleIssueEdited mocks base method.
+func (m *MockAppInterface) HandleIssueEdited(payload *github.IssuesEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleIssueEdited", payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleIssueEdited indicates an expected call of HandleIssueEdited.
+func (mr *MockAppInterfaceMockRecorder) HandleIssueEdited(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleIssueEdited", reflect.TypeOf((*MockAppInterface)(nil).HandleIssueEdited), payload)
+}
+
+// HandleIssueLabeled mocks base method.
+func (m *MockAppInterface) HandleIssueLabeled(payload *github.IssuesEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleIssueLabeled", payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleIssueLabeled indicates an expected call of HandleIssueLabeled.
+func (mr *MockAppInterfaceMockRecorder) HandleIssueLabeled(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleIssueLabeled", reflect.TypeOf((*MockAppInterface)(nil).HandleIssueLabeled), payload)
+}
+
 // HandleIssueOpened mocks base method.
 func (m *MockAppInterface) HandleIssueOpened(payload *github.IssuesEvent) error {
 	m.ctrl.T.Helper()
@@ -67,3 +152,73 @@ func (mr *MockAppInterfaceMockRecorder) HandleIssueOpened(payload any) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleIssueOpened", reflect.TypeOf((*MockAppInterface)(nil).HandleIssueOpened), payload)
 }
+
+// HandleIssueReopened mocks base method.
+func (m *MockAppInterface) HandleIssueReopened(payload *github.IssuesEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleIssueReopened", payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleIssueReopened indicates an expected call of HandleIssueReopened.
+func (mr *MockAppInterfaceMockRecorder) HandleIssueReopened(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleIssueReopened", reflect.TypeOf((*MockAppInterface)(nil).HandleIssueReopened), payload)
+}
+
+// HandleIssueTransferred mocks base method.
+func (m *MockAppInterface) HandleIssueTransferred(payload *app.IssueTransferredEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleIssueTransferred", payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleIssueTransferred indicates an expected call of HandleIssueTransferred.
+func (mr *MockAppInterfaceMockRecorder) HandleIssueTransferred(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleIssueTransferred", reflect.TypeOf((*MockAppInterface)(nil).HandleIssueTransferred), payload)
+}
+
+// HandleIssueUnlabeled mocks base method.
+func (m *MockAppInterface) HandleIssueUnlabeled(payload *github.IssuesEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleIssueUnlabeled", payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleIssueUnlabeled indicates an expected call of HandleIssueUnlabeled.
+func (mr *MockAppInterfaceMockRecorder) HandleIssueUnlabeled(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleIssueUnlabeled", reflect.TypeOf((*MockAppInterface)(nil).HandleIssueUnlabeled), payload)
+}
+
+// HandlePullRequest mocks base method.
+func (m *MockAppInterface) HandlePullRequest(payload *github.PullRequestEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandlePullRequest", payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandlePullRequest indicates an expected call of HandlePullRequest.
+func (mr *MockAppInterfaceMockRecorder) HandlePullRequest(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandlePullRequest", reflect.TypeOf((*MockAppInterface)(nil).HandlePullRequest), payload)
+}
+
+// RequiredEvents mocks base method.
+func (m *MockAppInterface) RequiredEvents() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequiredEvents")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// RequiredEvents indicates an expected call of RequiredEvents.
+func (mr *MockAppInterfaceMockRecorder) RequiredEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequiredEvents", reflect.TypeOf((*MockAppInterface)(nil).RequiredEvents))
+}